@@ -8,7 +8,6 @@ import (
 	"github.com/pvaronik/jito-go/pkg"
 	"github.com/pvaronik/jito-go/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 type Relayer struct {
@@ -34,53 +33,39 @@ type Validator struct {
 }
 
 func NewRelayer(grpcDialURL string, rpcClient *rpc.Client, privateKey solana.PrivateKey, tlsConfig *tls.Config, opts ...grpc.DialOption) (*Relayer, error) {
-	if tlsConfig != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
-	}
+	return NewRelayerWithOptions(grpcDialURL, rpcClient, privateKey, pkg.WithTLSConfig(tlsConfig), pkg.WithDialOptions(opts...))
+}
 
-	conn, err := pkg.CreateAndObserveGRPCConn(context.TODO(), grpcDialURL, opts...)
+// NewRelayerWithOptions creates a new Relayer configured by opts, see pkg.ClientOptions.
+func NewRelayerWithOptions(grpcDialURL string, rpcClient *rpc.Client, privateKey solana.PrivateKey, opts ...pkg.ClientOption) (*Relayer, error) {
+	conn, authService, err := pkg.NewClientOptions(opts...).NewAuthenticationService(grpcDialURL, privateKey, proto.Role_RELAYER)
 	if err != nil {
 		return nil, err
 	}
 
-	blockEngineRelayerClient := proto.NewBlockEngineRelayerClient(conn)
-	authService := pkg.NewAuthenticationService(conn, privateKey)
-	if err = authService.AuthenticateAndRefresh(proto.Role_RELAYER); err != nil {
-		return nil, err
-	}
-
 	return &Relayer{
 		GrpcConn: conn,
 		RpcConn:  rpcClient,
-		Client:   blockEngineRelayerClient,
-		Auth:     pkg.NewAuthenticationService(conn, privateKey),
+		Client:   proto.NewBlockEngineRelayerClient(conn),
+		Auth:     authService,
 	}, nil
 }
 
 func NewValidator(grpcDialURL string, rpcClient *rpc.Client, privateKey solana.PrivateKey, tlsConfig *tls.Config, opts ...grpc.DialOption) (*Validator, error) {
-	if tlsConfig != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
-	}
+	return NewValidatorWithOptions(grpcDialURL, rpcClient, privateKey, pkg.WithTLSConfig(tlsConfig), pkg.WithDialOptions(opts...))
+}
 
-	conn, err := pkg.CreateAndObserveGRPCConn(context.Background(), grpcDialURL, opts...)
+// NewValidatorWithOptions creates a new Validator configured by opts, see pkg.ClientOptions.
+func NewValidatorWithOptions(grpcDialURL string, rpcClient *rpc.Client, privateKey solana.PrivateKey, opts ...pkg.ClientOption) (*Validator, error) {
+	conn, authService, err := pkg.NewClientOptions(opts...).NewAuthenticationService(grpcDialURL, privateKey, proto.Role_VALIDATOR)
 	if err != nil {
 		return nil, err
 	}
 
-	blockEngineValidatorClient := proto.NewBlockEngineValidatorClient(conn)
-	authService := pkg.NewAuthenticationService(conn, privateKey)
-	if err = authService.AuthenticateAndRefresh(proto.Role_VALIDATOR); err != nil {
-		return nil, err
-	}
-
 	return &Validator{
 		GrpcConn: conn,
 		RpcConn:  rpcClient,
-		Client:   blockEngineValidatorClient,
+		Client:   proto.NewBlockEngineValidatorClient(conn),
 		Auth:     authService,
 	}, nil
 }
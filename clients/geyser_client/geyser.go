@@ -7,7 +7,6 @@ import (
 	"github.com/pvaronik/jito-go/pkg"
 	"github.com/pvaronik/jito-go/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 type Client struct {
@@ -21,24 +20,24 @@ type Client struct {
 
 // New creates a new RPC client and connects to the provided endpoint. A Geyser RPC URL is required.
 func New(ctx context.Context, grpcDialURL string, tlsConfig *tls.Config, opts ...grpc.DialOption) (*Client, error) {
-	if tlsConfig != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
-	}
+	return NewWithOptions(grpcDialURL, pkg.WithContext(ctx), pkg.WithTLSConfig(tlsConfig), pkg.WithDialOptions(opts...))
+}
 
-	conn, err := pkg.CreateAndObserveGRPCConn(ctx, grpcDialURL, opts...)
+// NewWithOptions creates a new RPC client configured by opts, see pkg.ClientOptions. A Geyser RPC URL is required.
+// The subscriptions are bound to the context set with pkg.WithContext.
+func NewWithOptions(grpcDialURL string, opts ...pkg.ClientOption) (*Client, error) {
+	options := pkg.NewClientOptions(opts...)
+
+	conn, err := options.Dial(grpcDialURL, nil, proto.Role_SEARCHER)
 	if err != nil {
 		return nil, err
 	}
 
-	geyserClient := proto.NewGeyserClient(conn)
-
 	return &Client{
 		GrpcConn: conn,
-		Geyser:   geyserClient,
+		Geyser:   proto.NewGeyserClient(conn),
 		ErrChan:  make(chan error),
-		Ctx:      ctx,
+		Ctx:      options.Ctx,
 	}, nil
 }
 
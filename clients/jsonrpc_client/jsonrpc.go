@@ -0,0 +1,347 @@
+package jsonrpc_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/clients/searcher_client"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+const (
+	BundlesPath      = "/api/v1/bundles"
+	TransactionsPath = "/api/v1/transactions"
+
+	// MaxBundleIds is the maximum number of bundles getBundleStatuses and getInflightBundleStatuses accept.
+	MaxBundleIds = 5
+)
+
+var (
+	ErrTooManyBundleIds = fmt.Errorf("more than %d bundle ids", MaxBundleIds)
+	ErrEmptyResult      = errors.New("jsonrpc response has no result")
+)
+
+// InflightStatus is the status of a bundle returned by getInflightBundleStatuses.
+type InflightStatus string
+
+const (
+	// InflightInvalid is returned for bundles unknown to the block engine, or older than 5 minutes.
+	InflightInvalid InflightStatus = "Invalid"
+	InflightPending InflightStatus = "Pending"
+	InflightFailed  InflightStatus = "Failed"
+	InflightLanded  InflightStatus = "Landed"
+)
+
+// Client sends bundles thru the JSON-RPC API of a block engine, which requires no authentication.
+type Client struct {
+	HTTPClient *http.Client
+	// BundlesURL and TransactionsURL are the endpoints of the bundles and transactions APIs.
+	BundlesURL      string
+	TransactionsURL string
+	// UUID is sent in the x-jito-auth header when not empty, for API keys with raised rate limits.
+	UUID string
+	// Validator checks bundles before they are sent, nil disables pre-flight validation.
+	Validator *searcher_client.BundleValidator
+	// PollInterval is the delay between two status polls of WatchBundle, defaults to 1s.
+	PollInterval time.Duration
+
+	id atomic.Uint64
+}
+
+// New creates a new JSON-RPC client of the block engine at host, e.g. jito_go.JitoBlockEngineMainnet or jito_go.Amsterdam.BlockEngineURL.
+// host may be a URL, https is used when it has no scheme. A nil httpClient falls back to http.DefaultClient.
+func New(host string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	base := strings.TrimSuffix(host, "/")
+	if !strings.Contains(base, "://") {
+		base = "https://" + base
+	}
+
+	return &Client{
+		HTTPClient:      httpClient,
+		BundlesURL:      base + BundlesPath,
+		TransactionsURL: base + TransactionsPath,
+		Validator:       searcher_client.NewBundleValidator(jito_go.MainnetTipAccounts, jito_go.TestnetTipAccounts),
+		PollInterval:    time.Second,
+	}
+}
+
+// RPCError is an error returned by the JSON-RPC API.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// HTTPError is returned when the API answers with a non 200 status and no JSON-RPC error, e.g. 429 when rate limited.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("http status %d: %s", e.StatusCode, e.Body)
+}
+
+// BundleStatus is the status of a landed bundle returned by getBundleStatuses.
+type BundleStatus struct {
+	BundleId           string                     `json:"bundle_id"`
+	Transactions       []solana.Signature         `json:"transactions"`
+	Slot               uint64                     `json:"slot"`
+	ConfirmationStatus rpc.ConfirmationStatusType `json:"confirmation_status"`
+	// Err is {"Ok":null} when the bundle landed successfully.
+	Err json.RawMessage `json:"err"`
+}
+
+// Failed reports whether the bundle landed with an error.
+func (s *BundleStatus) Failed() bool {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(s.Err, &result); err != nil {
+		return false
+	}
+	_, ok := result["Ok"]
+	return !ok && len(result) != 0
+}
+
+// Outcome converts the status to a searcher_client.BundleOutcome, finalized bundles are BundleStateFinalized.
+func (s *BundleStatus) Outcome() *searcher_client.BundleOutcome {
+	outcome := &searcher_client.BundleOutcome{BundleId: s.BundleId, State: searcher_client.BundleStateProcessed, Slot: s.Slot}
+	if s.ConfirmationStatus == rpc.ConfirmationStatusFinalized {
+		outcome.State = searcher_client.BundleStateFinalized
+	}
+	if s.Failed() {
+		outcome.Err = fmt.Errorf("%w: %s", searcher_client.ErrPartiallyProcessed, s.Err)
+	}
+	return outcome
+}
+
+// InflightBundleStatus is the status of a recent bundle returned by getInflightBundleStatuses.
+type InflightBundleStatus struct {
+	BundleId string         `json:"bundle_id"`
+	Status   InflightStatus `json:"status"`
+	// LandedSlot is set on Landed bundles.
+	LandedSlot *uint64 `json:"landed_slot"`
+}
+
+// Outcome converts the status to a searcher_client.BundleOutcome.
+// Pending bundles are accepted, landed ones processed, failed ones rejected and invalid ones unknown.
+func (s *InflightBundleStatus) Outcome() *searcher_client.BundleOutcome {
+	outcome := &searcher_client.BundleOutcome{BundleId: s.BundleId}
+
+	switch s.Status {
+	case InflightPending:
+		outcome.State = searcher_client.BundleStateAccepted
+	case InflightLanded:
+		outcome.State = searcher_client.BundleStateProcessed
+		if s.LandedSlot != nil {
+			outcome.Slot = *s.LandedSlot
+		}
+	case InflightFailed:
+		outcome.State = searcher_client.BundleStateRejected
+		outcome.Err = fmt.Errorf("%w: bundle %s failed", searcher_client.ErrBundleRejected, s.BundleId)
+	default:
+		outcome.State = searcher_client.BundleStateUnknown
+	}
+
+	return outcome
+}
+
+// TransactionSubmission is the result of SendTransaction.
+type TransactionSubmission struct {
+	Signature solana.Signature
+	// BundleId is the id of the bundle wrapping the transaction, sent in the x-bundle-id header.
+	BundleId string
+}
+
+// BroadcastBundle sends a bundle of transactions on chain thru Jito.
+func (c *Client) BroadcastBundle(ctx context.Context, transactions []pkg.Transaction) (*proto.SendBundleResponse, error) {
+	packets, err := pkg.ConvertBatchTransactionToProtobufPacket(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SendBundle(ctx, &proto.Bundle{Packets: packets})
+}
+
+// SendBundle sends an assembled bundle thru Jito, the Uuid of the response is the bundle id.
+func (c *Client) SendBundle(ctx context.Context, bundle *proto.Bundle) (*proto.SendBundleResponse, error) {
+	if c.Validator != nil {
+		if err := c.Validator.Validate(bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	encoded := make([]string, 0, len(bundle.GetPackets()))
+	for _, packet := range bundle.GetPackets() {
+		encoded = append(encoded, base64.StdEncoding.EncodeToString(packet.GetData()))
+	}
+
+	var bundleId string
+	if _, err := c.call(ctx, c.BundlesURL, "sendBundle", []interface{}{encoded, encodingConfig}, &bundleId); err != nil {
+		return nil, err
+	}
+
+	return &proto.SendBundleResponse{Uuid: bundleId}, nil
+}
+
+// GetBundleStatuses returns the statuses of landed bundles, in the order of bundleIds. Bundles not found have a nil status.
+func (c *Client) GetBundleStatuses(ctx context.Context, bundleIds []string) ([]*BundleStatus, error) {
+	if len(bundleIds) > MaxBundleIds {
+		return nil, ErrTooManyBundleIds
+	}
+
+	var out struct {
+		Value []*BundleStatus `json:"value"`
+	}
+	if _, err := c.call(ctx, c.BundlesURL, "getBundleStatuses", []interface{}{bundleIds}, &out); err != nil {
+		return nil, err
+	}
+
+	return alignStatuses(bundleIds, out.Value, func(s *BundleStatus) string { return s.BundleId }), nil
+}
+
+// GetInflightBundleStatuses returns the statuses of bundles sent in the last 5 minutes, in the order of bundleIds.
+func (c *Client) GetInflightBundleStatuses(ctx context.Context, bundleIds []string) ([]*InflightBundleStatus, error) {
+	if len(bundleIds) > MaxBundleIds {
+		return nil, ErrTooManyBundleIds
+	}
+
+	var out struct {
+		Value []*InflightBundleStatus `json:"value"`
+	}
+	if _, err := c.call(ctx, c.BundlesURL, "getInflightBundleStatuses", []interface{}{bundleIds}, &out); err != nil {
+		return nil, err
+	}
+
+	return alignStatuses(bundleIds, out.Value, func(s *InflightBundleStatus) string { return s.BundleId }), nil
+}
+
+// GetTipAccounts returns the tip accounts of the block engine.
+func (c *Client) GetTipAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	var accounts []solana.PublicKey
+	if _, err := c.call(ctx, c.BundlesURL, "getTipAccounts", []interface{}{}, &accounts); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// SendTransaction sends a single transaction thru Jito. With bundleOnly, it is sent as a single transaction bundle
+// whose id is returned, so it is never forwarded outside of Jito. The transaction must tip to land.
+func (c *Client) SendTransaction(ctx context.Context, tx pkg.Transaction, bundleOnly bool) (*TransactionSubmission, error) {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.TransactionsURL
+	if bundleOnly {
+		url += "?bundleOnly=true"
+	}
+
+	var signature solana.Signature
+	header, err := c.call(ctx, url, "sendTransaction", []interface{}{base64.StdEncoding.EncodeToString(data), encodingConfig}, &signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionSubmission{Signature: signature, BundleId: header.Get("x-bundle-id")}, nil
+}
+
+var encodingConfig = map[string]string{"encoding": "base64"}
+
+type request struct {
+	JsonRPC string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call posts a JSON-RPC request to url and decodes its result into out, returning the response headers.
+func (c *Client) call(ctx context.Context, url, method string, params interface{}, out interface{}) (http.Header, error) {
+	body, err := json.Marshal(request{JsonRPC: "2.0", ID: c.id.Add(1), Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.UUID != "" {
+		req.Header.Set("x-jito-auth", c.UUID)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded response
+	if err = json.Unmarshal(data, &decoded); err != nil || (decoded.Error == nil && resp.StatusCode != http.StatusOK) {
+		if resp.StatusCode != http.StatusOK {
+			return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+		}
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	if decoded.Error != nil {
+		return nil, decoded.Error
+	}
+	if len(decoded.Result) == 0 || string(decoded.Result) == "null" {
+		return nil, fmt.Errorf("%s: %w", method, ErrEmptyResult)
+	}
+
+	if err = json.Unmarshal(decoded.Result, out); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+
+	return resp.Header, nil
+}
+
+// alignStatuses orders statuses as ids, with nil for the ids missing from statuses.
+func alignStatuses[T any](ids []string, statuses []*T, id func(*T) string) []*T {
+	byId := make(map[string]*T, len(statuses))
+	for _, status := range statuses {
+		if status != nil {
+			byId[id(status)] = status
+		}
+	}
+
+	out := make([]*T, len(ids))
+	for i, bundleId := range ids {
+		out[i] = byId[bundleId]
+	}
+	return out
+}
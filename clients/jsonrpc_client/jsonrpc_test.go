@@ -0,0 +1,218 @@
+package jsonrpc_client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/clients/searcher_client"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// rpcCall is a request received by the fake block engine.
+type rpcCall struct {
+	Path   string
+	Query  string
+	Auth   string
+	Method string
+	Params []json.RawMessage
+}
+
+// newBlockEngineServer starts a fake JSON-RPC block engine answering with results[method], and records the first calls.
+// A func() interface{} result is called on every request.
+func newBlockEngineServer(t *testing.T, results map[string]interface{}) (*httptest.Server, chan rpcCall) {
+	calls := make(chan rpcCall, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		select {
+		case calls <- rpcCall{Path: r.URL.Path, Query: r.URL.RawQuery, Auth: r.Header.Get("x-jito-auth"), Method: req.Method, Params: req.Params}:
+		default:
+		}
+
+		if req.Method == "rateLimited" {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+
+		result, ok := results[req.Method]
+		if !ok {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"error":   map[string]interface{}{"code": -32601, "message": "Method not found"},
+			})
+			return
+		}
+
+		if f, ok := result.(func() interface{}); ok {
+			result = f()
+		}
+		if req.Method == "sendTransaction" {
+			w.Header().Set("x-bundle-id", "tx-bundle")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, calls
+}
+
+func Test_JsonRpcClient(t *testing.T) {
+	ctx := context.Background()
+	payer := solana.NewWallet().PrivateKey
+	signature := solana.Signature{7}
+	slot := uint64(42)
+
+	server, calls := newBlockEngineServer(t, map[string]interface{}{
+		"sendBundle":     "bundle",
+		"getTipAccounts": []string{jito_go.MainnetTipAccounts[0].String()},
+		"getBundleStatuses": map[string]interface{}{
+			"context": map[string]interface{}{"slot": 50},
+			"value": []interface{}{map[string]interface{}{
+				"bundle_id":           "b",
+				"transactions":        []string{signature.String()},
+				"slot":                slot,
+				"confirmation_status": "finalized",
+				"err":                 map[string]interface{}{"Ok": nil},
+			}},
+		},
+		"getInflightBundleStatuses": map[string]interface{}{
+			"context": map[string]interface{}{"slot": 50},
+			"value": []interface{}{
+				map[string]interface{}{"bundle_id": "a", "status": "Pending", "landed_slot": nil},
+				map[string]interface{}{"bundle_id": "b", "status": "Landed", "landed_slot": slot},
+				map[string]interface{}{"bundle_id": "c", "status": "Failed", "landed_slot": nil},
+			},
+		},
+		"sendTransaction": signature.String(),
+	})
+
+	client := New(server.URL, server.Client())
+	client.UUID = "key"
+
+	t.Run("New", func(t *testing.T) {
+		mainnet := New(jito_go.JitoBlockEngineMainnet, nil)
+		assert.Equal(t, "https://mainnet.block-engine.jito.wtf/api/v1/bundles", mainnet.BundlesURL)
+		assert.Equal(t, "https://amsterdam.mainnet.block-engine.jito.wtf:443/api/v1/transactions", New(jito_go.Amsterdam.BlockEngineURL, nil).TransactionsURL)
+	})
+
+	t.Run("SendBundle", func(t *testing.T) {
+		tx, err := solana.NewTransaction(
+			[]solana.Instruction{system.NewTransferInstruction(1000, payer.PublicKey(), jito_go.MainnetTipAccounts[0]).Build()},
+			solana.Hash{1},
+			solana.TransactionPayer(payer.PublicKey()),
+		)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		if _, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer }); !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		resp, err := client.BroadcastBundle(ctx, pkg.Transactions([]*solana.Transaction{tx}))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "bundle", resp.Uuid)
+
+		call := <-calls
+		assert.Equal(t, BundlesPath, call.Path)
+		assert.Equal(t, "key", call.Auth)
+		assert.Equal(t, "sendBundle", call.Method)
+
+		var encoded []string
+		assert.NoError(t, json.Unmarshal(call.Params[0], &encoded))
+		expected, _ := tx.MarshalBinary()
+		assert.Equal(t, []string{base64.StdEncoding.EncodeToString(expected)}, encoded)
+		assert.JSONEq(t, `{"encoding":"base64"}`, string(call.Params[1]))
+
+		_, err = client.BroadcastBundle(ctx, pkg.Transactions([]*solana.Transaction{}))
+		assert.Error(t, err, "the bundle is validated before being sent")
+	})
+
+	t.Run("GetBundleStatuses", func(t *testing.T) {
+		statuses, err := client.GetBundleStatuses(ctx, []string{"a", "b"})
+		if !assert.NoError(t, err) || !assert.Len(t, statuses, 2) {
+			t.FailNow()
+		}
+		<-calls
+
+		assert.Nil(t, statuses[0])
+		assert.Equal(t, []solana.Signature{signature}, statuses[1].Transactions)
+		assert.False(t, statuses[1].Failed())
+
+		outcome := statuses[1].Outcome()
+		assert.Equal(t, searcher_client.BundleStateFinalized, outcome.State)
+		assert.Equal(t, slot, outcome.Slot)
+		assert.NoError(t, outcome.Err)
+
+		_, err = client.GetBundleStatuses(ctx, make([]string, MaxBundleIds+1))
+		assert.ErrorIs(t, err, ErrTooManyBundleIds)
+	})
+
+	t.Run("GetInflightBundleStatuses", func(t *testing.T) {
+		statuses, err := client.GetInflightBundleStatuses(ctx, []string{"c", "b", "a"})
+		if !assert.NoError(t, err) || !assert.Len(t, statuses, 3) {
+			t.FailNow()
+		}
+		<-calls
+
+		assert.Equal(t, searcher_client.BundleStateRejected, statuses[0].Outcome().State)
+		assert.ErrorIs(t, statuses[0].Outcome().Err, searcher_client.ErrBundleRejected)
+		assert.Equal(t, searcher_client.BundleStateProcessed, statuses[1].Outcome().State)
+		assert.Equal(t, slot, statuses[1].Outcome().Slot)
+		assert.Equal(t, searcher_client.BundleStateAccepted, statuses[2].Outcome().State)
+	})
+
+	t.Run("GetTipAccounts", func(t *testing.T) {
+		accounts, err := client.GetTipAccounts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, jito_go.MainnetTipAccounts[:1], accounts)
+		<-calls
+	})
+
+	t.Run("SendTransaction", func(t *testing.T) {
+		submission, err := client.SendTransaction(ctx, pkg.RawTransaction{1, 2, 3}, true)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, signature, submission.Signature)
+		assert.Equal(t, "tx-bundle", submission.BundleId)
+
+		call := <-calls
+		assert.Equal(t, TransactionsPath, call.Path)
+		assert.Equal(t, "bundleOnly=true", call.Query)
+		assert.JSONEq(t, `"AQID"`, string(call.Params[0]))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var rpcErr *RPCError
+		_, err := client.call(ctx, client.BundlesURL, "unknown", []interface{}{}, new(string))
+		if assert.ErrorAs(t, err, &rpcErr) {
+			assert.Equal(t, -32601, rpcErr.Code)
+		}
+		<-calls
+
+		var httpErr *HTTPError
+		_, err = client.call(ctx, client.BundlesURL, "rateLimited", []interface{}{}, new(string))
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+		}
+		<-calls
+	})
+}
@@ -0,0 +1,104 @@
+package jsonrpc_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go/clients/searcher_client"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// ErrNoLeaderSchedule is returned by GetNextScheduledLeader, the JSON-RPC API does not expose the connected leaders.
+var ErrNoLeaderSchedule = fmt.Errorf("next scheduled leader over JSON-RPC: %w", errors.ErrUnsupported)
+
+var _ searcher_client.Searcher = (*Client)(nil)
+
+// GetNextScheduledLeader always fails with ErrNoLeaderSchedule.
+func (c *Client) GetNextScheduledLeader(context.Context, []string) (*proto.NextScheduledLeaderResponse, error) {
+	return nil, ErrNoLeaderSchedule
+}
+
+// WatchBundle polls the status of bundleId every PollInterval and streams its changes.
+// The in-flight status is polled until the bundle lands, then its status until it is finalized.
+// A bundle which was pending and becomes invalid is dropped, as expired. Polling errors are retried until ctx is done.
+func (c *Client) WatchBundle(ctx context.Context, bundleId string) <-chan *searcher_client.BundleOutcome {
+	outcomes := make(chan *searcher_client.BundleOutcome)
+	go c.watch(ctx, bundleId, outcomes)
+	return outcomes
+}
+
+func (c *Client) watch(ctx context.Context, bundleId string, outcomes chan<- *searcher_client.BundleOutcome) {
+	defer close(outcomes)
+
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	last := searcher_client.BundleStateUnknown
+	emit := func(outcome *searcher_client.BundleOutcome) bool {
+		if outcome.State == last {
+			return true
+		}
+		last = outcome.State
+
+		select {
+		case outcomes <- outcome:
+			return !outcome.Final()
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		outcome := c.poll(ctx, bundleId, last)
+		if outcome != nil && !emit(outcome) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// poll returns the current outcome of bundleId, nil when it is unknown or could not be fetched.
+func (c *Client) poll(ctx context.Context, bundleId string, last searcher_client.BundleState) *searcher_client.BundleOutcome {
+	if last != searcher_client.BundleStateProcessed {
+		inflight, err := c.GetInflightBundleStatuses(ctx, []string{bundleId})
+		if err != nil || inflight[0] == nil {
+			return nil
+		}
+
+		outcome := inflight[0].Outcome()
+		if outcome.State == searcher_client.BundleStateUnknown {
+			if last != searcher_client.BundleStateAccepted {
+				return nil
+			}
+			return &searcher_client.BundleOutcome{
+				BundleId: bundleId,
+				State:    searcher_client.BundleStateDropped,
+				Err:      &searcher_client.BundleDroppedError{BundleId: bundleId, DroppedReason: proto.DroppedReason_BlockhashExpired},
+			}
+		}
+		if outcome.State != searcher_client.BundleStateProcessed {
+			return outcome
+		}
+	}
+
+	statuses, err := c.GetBundleStatuses(ctx, []string{bundleId})
+	if err != nil || statuses[0] == nil {
+		return nil
+	}
+
+	outcome := statuses[0].Outcome()
+	if statuses[0].ConfirmationStatus != rpc.ConfirmationStatusFinalized {
+		outcome.State = searcher_client.BundleStateProcessed
+	}
+	return outcome
+}
@@ -0,0 +1,89 @@
+package jsonrpc_client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/clients/searcher_client"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Searcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses := func(values ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": values}
+	}
+
+	watch := func(t *testing.T, inflight, landed []interface{}) []*searcher_client.BundleOutcome {
+		var inflightPolls, landedPolls atomic.Int64
+		next := func(polls *atomic.Int64, script []interface{}) interface{} {
+			return script[min(int(polls.Add(1)), len(script))-1]
+		}
+
+		server, _ := newBlockEngineServer(t, map[string]interface{}{
+			"getInflightBundleStatuses": func() interface{} { return next(&inflightPolls, inflight) },
+			"getBundleStatuses":         func() interface{} { return next(&landedPolls, landed) },
+		})
+		client := New(server.URL, server.Client())
+		client.PollInterval = time.Millisecond
+
+		var outcomes []*searcher_client.BundleOutcome
+		for outcome := range client.WatchBundle(ctx, "b") {
+			outcomes = append(outcomes, outcome)
+		}
+		assert.NoError(t, ctx.Err())
+		return outcomes
+	}
+
+	states := func(outcomes []*searcher_client.BundleOutcome) []searcher_client.BundleState {
+		var out []searcher_client.BundleState
+		for _, outcome := range outcomes {
+			out = append(out, outcome.State)
+		}
+		return out
+	}
+
+	t.Run("Landed", func(t *testing.T) {
+		outcomes := watch(t,
+			[]interface{}{
+				statuses(map[string]interface{}{"bundle_id": "b", "status": "Invalid", "landed_slot": nil}),
+				statuses(map[string]interface{}{"bundle_id": "b", "status": "Pending", "landed_slot": nil}),
+				statuses(map[string]interface{}{"bundle_id": "b", "status": "Landed", "landed_slot": 9}),
+			},
+			[]interface{}{
+				statuses(map[string]interface{}{"bundle_id": "b", "slot": 9, "confirmation_status": "confirmed", "err": map[string]interface{}{"Ok": nil}}),
+				statuses(map[string]interface{}{"bundle_id": "b", "slot": 9, "confirmation_status": "finalized", "err": map[string]interface{}{"Ok": nil}}),
+			},
+		)
+
+		assert.Equal(t, []searcher_client.BundleState{
+			searcher_client.BundleStateAccepted,
+			searcher_client.BundleStateProcessed,
+			searcher_client.BundleStateFinalized,
+		}, states(outcomes))
+		assert.Equal(t, uint64(9), outcomes[1].Slot)
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		outcomes := watch(t,
+			[]interface{}{
+				statuses(map[string]interface{}{"bundle_id": "b", "status": "Pending", "landed_slot": nil}),
+				statuses(map[string]interface{}{"bundle_id": "b", "status": "Invalid", "landed_slot": nil}),
+			},
+			nil,
+		)
+
+		if assert.Equal(t, []searcher_client.BundleState{searcher_client.BundleStateAccepted, searcher_client.BundleStateDropped}, states(outcomes)) {
+			assert.ErrorIs(t, outcomes[1].Err, searcher_client.ErrBundleDropped)
+		}
+	})
+
+	t.Run("NextScheduledLeader", func(t *testing.T) {
+		_, err := New("localhost", nil).GetNextScheduledLeader(ctx, nil)
+		assert.ErrorIs(t, err, ErrNoLeaderSchedule)
+	})
+}
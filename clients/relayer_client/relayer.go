@@ -1,13 +1,11 @@
 package relayer_client
 
 import (
-	"context"
 	"crypto/tls"
 	"github.com/gagliardetto/solana-go"
 	"github.com/pvaronik/jito-go/pkg"
 	"github.com/pvaronik/jito-go/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 type Client struct {
@@ -18,26 +16,19 @@ type Client struct {
 }
 
 func New(grpcDialURL string, privateKey solana.PrivateKey, tlsConfig *tls.Config, opts ...grpc.DialOption) (*Client, error) {
-	if tlsConfig != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
-	}
+	return NewWithOptions(grpcDialURL, privateKey, pkg.WithTLSConfig(tlsConfig), pkg.WithDialOptions(opts...))
+}
 
-	conn, err := pkg.CreateAndObserveGRPCConn(context.Background(), grpcDialURL, opts...)
+// NewWithOptions creates a new Relayer Client configured by opts, see pkg.ClientOptions.
+func NewWithOptions(grpcDialURL string, privateKey solana.PrivateKey, opts ...pkg.ClientOption) (*Client, error) {
+	conn, authService, err := pkg.NewClientOptions(opts...).NewAuthenticationService(grpcDialURL, privateKey, proto.Role_RELAYER)
 	if err != nil {
 		return nil, err
 	}
 
-	relayerClient := proto.NewRelayerClient(conn)
-	authService := pkg.NewAuthenticationService(conn, privateKey)
-	if err = authService.AuthenticateAndRefresh(proto.Role_RELAYER); err != nil {
-		return nil, err
-	}
-
 	return &Client{
 		GrpcConn: conn,
-		Relayer:  relayerClient,
+		Relayer:  proto.NewRelayerClient(conn),
 		Auth:     authService,
 	}, nil
 }
@@ -0,0 +1,222 @@
+package searcher_client
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/pkg"
+)
+
+// TokenAccountSize is the size of an SPL token account without extensions.
+const TokenAccountSize = 165
+
+// token2022AccountType is the AccountType::Account byte following the base state of extended Token-2022 accounts.
+const token2022AccountType = 2
+
+var ErrAccountsMismatch = errors.New("simulated accounts do not match the requested addresses")
+
+// AccountDiff is the change of an account between its pre and post execution state.
+type AccountDiff struct {
+	Address solana.PublicKey
+	// Pre and Post are nil when the account did not exist.
+	Pre  *Account
+	Post *Account
+	// LamportsDelta is the post balance minus the pre balance.
+	LamportsDelta int64
+	OwnerChanged  bool
+	// DataChanges are the byte ranges whose content differs, in ascending offset.
+	DataChanges []DataChange
+	// Token is set when the pre or post owner is the SPL token or Token-2022 program.
+	Token *TokenBalanceDiff
+}
+
+// Changed reports whether the account was modified.
+func (d AccountDiff) Changed() bool {
+	return d.LamportsDelta != 0 || d.OwnerChanged || len(d.DataChanges) != 0 || (d.Pre == nil) != (d.Post == nil)
+}
+
+// DataChange is a contiguous range of modified account data. Pre or Post is shorter when the data was resized.
+type DataChange struct {
+	Offset int
+	Pre    []byte
+	Post   []byte
+}
+
+// TokenBalance is the decoded state of an SPL token account.
+type TokenBalance struct {
+	Mint   solana.PublicKey
+	Owner  solana.PublicKey
+	Amount uint64
+}
+
+// TokenBalanceDiff is the change of an SPL token account, Pre or Post is nil when it is not a token account.
+type TokenBalanceDiff struct {
+	Pre  *TokenBalance
+	Post *TokenBalance
+	// Delta is the post amount minus the pre amount.
+	Delta *big.Int
+}
+
+// DecodeTokenBalance decodes the SPL token account data of account, false if it is not owned by a token program.
+func DecodeTokenBalance(account *Account) (*TokenBalance, bool) {
+	if account == nil || !(account.Owner.Equals(solana.TokenProgramID) || account.Owner.Equals(pkg.Token2022ProgramID)) {
+		return nil, false
+	}
+
+	data := account.DataBytes()
+	switch {
+	case len(data) == TokenAccountSize:
+	case len(data) > TokenAccountSize && account.Owner.Equals(pkg.Token2022ProgramID) && data[TokenAccountSize] == token2022AccountType:
+		// Token-2022 accounts with extensions, extended mints are padded to the same size but typed as mints
+	default:
+		// mints and multisigs share the token programs, their sizes differ from token accounts
+		return nil, false
+	}
+
+	return &TokenBalance{
+		Mint:   solana.PublicKeyFromBytes(data[0:32]),
+		Owner:  solana.PublicKeyFromBytes(data[32:64]),
+		Amount: binary.LittleEndian.Uint64(data[64:72]),
+	}, true
+}
+
+// DiffAccount compares the pre and post state of address, nil states are accounts which do not exist.
+func DiffAccount(address solana.PublicKey, pre, post *Account) AccountDiff {
+	pre, post = existing(pre), existing(post)
+	diff := AccountDiff{Address: address, Pre: pre, Post: post}
+
+	var preLamports, postLamports uint64
+	var preOwner, postOwner solana.PublicKey
+	var preData, postData []byte
+	if pre != nil {
+		preLamports, preOwner, preData = pre.Lamports, pre.Owner, pre.DataBytes()
+	}
+	if post != nil {
+		postLamports, postOwner, postData = post.Lamports, post.Owner, post.DataBytes()
+	}
+
+	diff.LamportsDelta = int64(postLamports - preLamports)
+	diff.OwnerChanged = !preOwner.Equals(postOwner)
+	diff.DataChanges = diffData(preData, postData)
+
+	preToken, preOk := DecodeTokenBalance(pre)
+	postToken, postOk := DecodeTokenBalance(post)
+	if preOk || postOk {
+		diff.Token = &TokenBalanceDiff{Pre: preToken, Post: postToken, Delta: new(big.Int)}
+		if postOk {
+			diff.Token.Delta.SetUint64(postToken.Amount)
+		}
+		if preOk {
+			diff.Token.Delta.Sub(diff.Token.Delta, new(big.Int).SetUint64(preToken.Amount))
+		}
+	}
+
+	return diff
+}
+
+// TransactionDiffs returns the account diffs of each transaction of the bundle, config is the one passed to SimulateBundle.
+// The diffs of a transaction are in the order of its PreExecutionAccountsConfigs addresses, which must match its post addresses.
+func (r *SimulatedBundleResponse) TransactionDiffs(config SimulateBundleConfig) ([][]AccountDiff, error) {
+	results := r.Value.TransactionResult
+	if len(config.PreExecutionAccountsConfigs) < len(results) || len(config.PostExecutionAccountsConfigs) < len(results) {
+		return nil, ErrSimulationConfigLength
+	}
+
+	out := make([][]AccountDiff, len(results))
+	for i, result := range results {
+		pre, err := accountsByAddress(config.PreExecutionAccountsConfigs[i], result.PreExecutionAccounts)
+		if err != nil {
+			return nil, fmt.Errorf("%d: pre execution: %w", i, err)
+		}
+		post, err := accountsByAddress(config.PostExecutionAccountsConfigs[i], result.PostExecutionAccounts)
+		if err != nil {
+			return nil, fmt.Errorf("%d: post execution: %w", i, err)
+		}
+
+		for _, address := range config.PreExecutionAccountsConfigs[i].Addresses {
+			key, err := solana.PublicKeyFromBase58(address)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			out[i] = append(out[i], DiffAccount(key, pre[address], post[address]))
+		}
+	}
+
+	return out, nil
+}
+
+// BundleDiff returns the net change of every requested address over the bundle,
+// from its state before the first transaction requesting it to its state after the last one.
+func (r *SimulatedBundleResponse) BundleDiff(config SimulateBundleConfig) ([]AccountDiff, error) {
+	txDiffs, err := r.TransactionDiffs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []solana.PublicKey
+	first := make(map[solana.PublicKey]*Account)
+	last := make(map[solana.PublicKey]*Account)
+	for _, diffs := range txDiffs {
+		for _, diff := range diffs {
+			if _, ok := last[diff.Address]; !ok {
+				order = append(order, diff.Address)
+				first[diff.Address] = diff.Pre
+			}
+			last[diff.Address] = diff.Post
+		}
+	}
+
+	out := make([]AccountDiff, 0, len(order))
+	for _, address := range order {
+		out = append(out, DiffAccount(address, first[address], last[address]))
+	}
+	return out, nil
+}
+
+func accountsByAddress(config ExecutionAccounts, accounts []Account) (map[string]*Account, error) {
+	if len(config.Addresses) != len(accounts) {
+		return nil, fmt.Errorf("%w: %d accounts for %d addresses", ErrAccountsMismatch, len(accounts), len(config.Addresses))
+	}
+
+	out := make(map[string]*Account, len(accounts))
+	for i := range accounts {
+		out[config.Addresses[i]] = &accounts[i]
+	}
+	return out, nil
+}
+
+// existing returns nil for the zero Account simulateBundle returns in place of accounts which do not exist.
+func existing(account *Account) *Account {
+	if account == nil || (account.Lamports == 0 && account.Owner.IsZero() && len(account.DataBytes()) == 0) {
+		return nil
+	}
+	return account
+}
+
+// diffData returns the contiguous byte ranges which differ between pre and post.
+func diffData(pre, post []byte) []DataChange {
+	var changes []DataChange
+
+	common := min(len(pre), len(post))
+	for i := 0; i < common; {
+		if pre[i] == post[i] {
+			i++
+			continue
+		}
+
+		start := i
+		for i < common && pre[i] != post[i] {
+			i++
+		}
+		changes = append(changes, DataChange{Offset: start, Pre: pre[start:i], Post: post[start:i]})
+	}
+
+	if len(pre) != len(post) {
+		changes = append(changes, DataChange{Offset: common, Pre: pre[common:], Post: post[common:]})
+	}
+
+	return changes
+}
@@ -0,0 +1,166 @@
+package searcher_client
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenAccountData returns the data of a token account holding amount of mint.
+func tokenAccountData(mint, owner solana.PublicKey, amount uint64) []byte {
+	data := make([]byte, TokenAccountSize)
+	copy(data[0:32], mint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], amount)
+	return data
+}
+
+// simulatedAccount returns the JSON of an account as returned by simulateBundle.
+func simulatedAccount(owner solana.PublicKey, lamports uint64, data []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"executable": false,
+		"owner":      owner.String(),
+		"lamports":   lamports,
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"rentEpoch":  0,
+	}
+}
+
+func Test_AccountDiff(t *testing.T) {
+	wallet := solana.NewWallet().PublicKey()
+	tokenAccount := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+
+	config := NewSimulateBundleConfig(2, wallet, tokenAccount)
+
+	var resp SimulatedBundleResponse
+	raw, err := json.Marshal(map[string]interface{}{
+		"context": map[string]interface{}{"slot": 1},
+		"value": map[string]interface{}{
+			"summary": "succeeded",
+			"transactionResults": []interface{}{
+				map[string]interface{}{
+					"preExecutionAccounts": []interface{}{
+						simulatedAccount(solana.SystemProgramID, 1000, nil),
+						simulatedAccount(solana.TokenProgramID, 10, tokenAccountData(mint, wallet, 5)),
+					},
+					"postExecutionAccounts": []interface{}{
+						simulatedAccount(solana.SystemProgramID, 900, nil),
+						simulatedAccount(solana.TokenProgramID, 10, tokenAccountData(mint, wallet, 50)),
+					},
+				},
+				map[string]interface{}{
+					"preExecutionAccounts": []interface{}{
+						simulatedAccount(solana.SystemProgramID, 900, nil),
+						simulatedAccount(solana.TokenProgramID, 10, tokenAccountData(mint, wallet, 50)),
+					},
+					"postExecutionAccounts": []interface{}{
+						simulatedAccount(solana.SystemProgramID, 1500, nil),
+						nil,
+					},
+				},
+			},
+		},
+	})
+	if !assert.NoError(t, err) || !assert.NoError(t, json.Unmarshal(raw, &resp)) {
+		t.FailNow()
+	}
+
+	t.Run("TransactionDiffs", func(t *testing.T) {
+		diffs, err := resp.TransactionDiffs(config)
+		if !assert.NoError(t, err) || !assert.Len(t, diffs, 2) {
+			t.FailNow()
+		}
+
+		first := diffs[0]
+		assert.Equal(t, wallet, first[0].Address)
+		assert.Equal(t, int64(-100), first[0].LamportsDelta)
+		assert.Nil(t, first[0].Token)
+
+		token := first[1]
+		assert.True(t, token.Changed())
+		assert.Zero(t, token.LamportsDelta)
+		assert.False(t, token.OwnerChanged)
+		assert.Equal(t, []DataChange{{Offset: 64, Pre: []byte{5}, Post: []byte{50}}}, token.DataChanges)
+		if assert.NotNil(t, token.Token) {
+			assert.Equal(t, mint, token.Token.Post.Mint)
+			assert.Equal(t, wallet, token.Token.Post.Owner)
+			assert.Equal(t, big.NewInt(45), token.Token.Delta)
+		}
+
+		closed := diffs[1][1]
+		assert.Nil(t, closed.Post)
+		assert.True(t, closed.OwnerChanged)
+		assert.Equal(t, int64(-10), closed.LamportsDelta)
+		assert.Equal(t, big.NewInt(-50), closed.Token.Delta)
+		if assert.Len(t, closed.DataChanges, 1) {
+			assert.Len(t, closed.DataChanges[0].Pre, TokenAccountSize)
+			assert.Empty(t, closed.DataChanges[0].Post)
+		}
+	})
+
+	t.Run("BundleDiff", func(t *testing.T) {
+		diffs, err := resp.BundleDiff(config)
+		if !assert.NoError(t, err) || !assert.Len(t, diffs, 2) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, int64(500), diffs[0].LamportsDelta)
+		assert.Equal(t, big.NewInt(-5), diffs[1].Token.Delta)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		_, err := resp.TransactionDiffs(NewSimulateBundleConfig(2, wallet))
+		assert.ErrorIs(t, err, ErrAccountsMismatch)
+	})
+
+	t.Run("DecodeTokenBalance", func(t *testing.T) {
+		account := func(owner solana.PublicKey, data []byte) *Account {
+			return &Account{Owner: owner, Data: rpc.DataBytesOrJSONFromBytes(data)}
+		}
+
+		balance, ok := DecodeTokenBalance(account(solana.TokenProgramID, tokenAccountData(mint, wallet, 7)))
+		if assert.True(t, ok) {
+			assert.Equal(t, &TokenBalance{Mint: mint, Owner: wallet, Amount: 7}, balance)
+		}
+
+		// Token-2022 account with extensions: AccountType::Account then the extensions
+		extended := append(tokenAccountData(mint, wallet, 9), 2, 7, 0, 0, 0)
+		balance, ok = DecodeTokenBalance(account(pkg.Token2022ProgramID, extended))
+		if assert.True(t, ok) {
+			assert.Equal(t, uint64(9), balance.Amount)
+		}
+
+		// extended token accounts only exist under Token-2022
+		_, ok = DecodeTokenBalance(account(solana.TokenProgramID, extended))
+		assert.False(t, ok)
+
+		// multisig accounts are 355 bytes
+		_, ok = DecodeTokenBalance(account(solana.TokenProgramID, make([]byte, 355)))
+		assert.False(t, ok)
+
+		// Token-2022 mint with extensions: padded to the token account size, then AccountType::Mint
+		extendedMint := append(make([]byte, TokenAccountSize), 1, 7, 0, 0, 0)
+		_, ok = DecodeTokenBalance(account(pkg.Token2022ProgramID, extendedMint))
+		assert.False(t, ok)
+
+		_, ok = DecodeTokenBalance(account(solana.TokenProgramID, make([]byte, 82)))
+		assert.False(t, ok)
+	})
+
+	t.Run("DiffData", func(t *testing.T) {
+		changes := diffData([]byte{1, 2, 3, 4, 5}, []byte{1, 9, 9, 4, 6, 7})
+		assert.Equal(t, []DataChange{
+			{Offset: 1, Pre: []byte{2, 3}, Post: []byte{9, 9}},
+			{Offset: 4, Pre: []byte{5}, Post: []byte{6}},
+			{Offset: 5, Pre: []byte{}, Post: []byte{7}},
+		}, changes)
+	})
+}
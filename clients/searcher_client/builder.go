@@ -0,0 +1,309 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// MaxBundleTransactions is the maximum number of transactions Jito accepts in a bundle.
+const MaxBundleTransactions = 5
+
+var (
+	ErrTooManyTransactions = fmt.Errorf("bundle exceeds %d transactions", MaxBundleTransactions)
+	ErrEmptyBundle         = errors.New("bundle has no transaction")
+	ErrNoPayer             = errors.New("bundle builder has no payer nor signer")
+)
+
+// TipPlacement is where BundleBuilder puts the tip instruction.
+type TipPlacement int
+
+const (
+	// TipInLastTransaction appends the tip to the last transaction of the bundle, if the builder built it.
+	// A separate tip transaction is used when the last transaction was added with AddTransaction.
+	TipInLastTransaction TipPlacement = iota
+	// TipInSeparateTransaction appends a dedicated tip transaction to the bundle.
+	TipInSeparateTransaction
+)
+
+// bundleEntry is either a set of instructions the builder compiles to a transaction, or a prebuilt transaction.
+type bundleEntry struct {
+	instructions []solana.Instruction
+	tx           *solana.Transaction
+}
+
+// BundleBuilder assembles, tips and signs a bundle ready for SendBundle.
+// Methods record errors which are returned by Build, so calls can be chained.
+type BundleBuilder struct {
+	client *Client
+
+	entries       []bundleEntry
+	signers       []solana.PrivateKey
+	payer         *solana.PublicKey
+	addressTables map[solana.PublicKey]solana.PublicKeySlice
+
+	tipLamports  uint64
+	tipStrategy  *TipStrategy
+	tipPayer     *solana.PublicKey
+	tipAccount   func() (solana.PublicKey, error)
+	tipPlacement TipPlacement
+
+	blockhash  *solana.Hash
+	commitment rpc.CommitmentType
+
+	err error
+}
+
+// NewBundleBuilder creates a BundleBuilder. Without WithTipAccount the tip goes to a random tip account.
+func (c *Client) NewBundleBuilder() *BundleBuilder {
+	return &BundleBuilder{
+		client:     c,
+		commitment: rpc.CommitmentFinalized,
+	}
+}
+
+// AddInstructions adds a transaction made of the provided instructions.
+func (b *BundleBuilder) AddInstructions(instructions ...solana.Instruction) *BundleBuilder {
+	if len(instructions) == 0 {
+		b.setErr(errors.New("AddInstructions: no instruction provided"))
+		return b
+	}
+
+	b.entries = append(b.entries, bundleEntry{instructions: instructions})
+	return b
+}
+
+// AddTransaction adds a prebuilt transaction. It is signed by Build only if it has no signature yet.
+func (b *BundleBuilder) AddTransaction(tx *solana.Transaction) *BundleBuilder {
+	if tx == nil {
+		b.setErr(errors.New("AddTransaction: nil transaction"))
+		return b
+	}
+
+	b.entries = append(b.entries, bundleEntry{tx: tx})
+	return b
+}
+
+// WithSigners adds the keys signing the transactions, the first one is the default payer.
+func (b *BundleBuilder) WithSigners(signers ...solana.PrivateKey) *BundleBuilder {
+	b.signers = append(b.signers, signers...)
+	return b
+}
+
+// WithPayer sets the fee payer of the transactions built from instructions.
+func (b *BundleBuilder) WithPayer(payer solana.PublicKey) *BundleBuilder {
+	b.payer = &payer
+	return b
+}
+
+// WithAddressTables compiles the transactions built from instructions as v0 transactions using these lookup tables.
+func (b *BundleBuilder) WithAddressTables(tables map[solana.PublicKey]solana.PublicKeySlice) *BundleBuilder {
+	b.addressTables = tables
+	return b
+}
+
+// WithTip sets the amount transferred to the tip account, a bundle without tip is built when it is 0.
+func (b *BundleBuilder) WithTip(lamports uint64) *BundleBuilder {
+	b.tipLamports = lamports
+	return b
+}
+
+// WithTipStrategy tips the amount picked by strategy when building, overriding WithTip.
+func (b *BundleBuilder) WithTipStrategy(strategy *TipStrategy) *BundleBuilder {
+	b.tipStrategy = strategy
+	return b
+}
+
+// WithTipPayer sets the account paying the tip, defaults to the payer.
+func (b *BundleBuilder) WithTipPayer(tipPayer solana.PublicKey) *BundleBuilder {
+	b.tipPayer = &tipPayer
+	return b
+}
+
+// WithTipAccount tips a fixed tip account.
+func (b *BundleBuilder) WithTipAccount(tipAccount solana.PublicKey) *BundleBuilder {
+	b.tipAccount = func() (solana.PublicKey, error) { return tipAccount, nil }
+	return b
+}
+
+// WithTipAccountFunc tips the account returned by pick, allowing custom tip account strategies.
+func (b *BundleBuilder) WithTipAccountFunc(pick func() (solana.PublicKey, error)) *BundleBuilder {
+	b.tipAccount = pick
+	return b
+}
+
+// WithTipPlacement sets where the tip instruction goes, defaults to TipInLastTransaction.
+func (b *BundleBuilder) WithTipPlacement(placement TipPlacement) *BundleBuilder {
+	b.tipPlacement = placement
+	return b
+}
+
+// WithBlockhash uses the provided blockhash instead of fetching a recent one from RpcConn.
+func (b *BundleBuilder) WithBlockhash(blockhash solana.Hash) *BundleBuilder {
+	b.blockhash = &blockhash
+	return b
+}
+
+// WithCommitment sets the commitment used to fetch the recent blockhash, defaults to finalized.
+func (b *BundleBuilder) WithCommitment(commitment rpc.CommitmentType) *BundleBuilder {
+	b.commitment = commitment
+	return b
+}
+
+// BuildTransactions compiles and signs the transactions of the bundle.
+func (b *BundleBuilder) BuildTransactions(ctx context.Context) ([]*solana.Transaction, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(b.entries) == 0 {
+		return nil, ErrEmptyBundle
+	}
+
+	payer, err := b.resolvePayer()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]bundleEntry, len(b.entries))
+	copy(entries, b.entries)
+
+	last := &entries[len(entries)-1]
+	tipInLast := b.tipPlacement == TipInLastTransaction && last.tx == nil
+
+	tipLamports := b.tipLamports
+	if b.tipStrategy != nil {
+		tipLamports = b.tipStrategy.Tip()
+	}
+
+	count := len(entries)
+	if tipLamports > 0 && !tipInLast {
+		count++
+	}
+	if count > MaxBundleTransactions {
+		return nil, ErrTooManyTransactions
+	}
+
+	if tipLamports > 0 {
+		var tipInst solana.Instruction
+		tipInst, err = b.tipInstruction(payer, tipLamports)
+		if err != nil {
+			return nil, err
+		}
+
+		if tipInLast {
+			last.instructions = append(append([]solana.Instruction{}, last.instructions...), tipInst)
+		} else {
+			entries = append(entries, bundleEntry{instructions: []solana.Instruction{tipInst}})
+		}
+	}
+
+	blockhash, err := b.recentBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []solana.TransactionOption{solana.TransactionPayer(payer)}
+	if b.addressTables != nil {
+		opts = append(opts, solana.TransactionAddressTables(b.addressTables))
+	}
+
+	txns := make([]*solana.Transaction, 0, len(entries))
+	for i, entry := range entries {
+		tx := entry.tx
+		if tx == nil {
+			tx, err = solana.NewTransaction(entry.instructions, blockhash, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("%d: error building transaction [%w]", i, err)
+			}
+		}
+
+		if len(tx.Signatures) == 0 {
+			if _, err = tx.Sign(b.signer); err != nil {
+				return nil, fmt.Errorf("%d: error signing transaction [%w]", i, err)
+			}
+		}
+
+		txns = append(txns, tx)
+	}
+
+	return txns, nil
+}
+
+// Build compiles and signs the bundle.
+func (b *BundleBuilder) Build(ctx context.Context) (*proto.Bundle, error) {
+	txns, err := b.BuildTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packets, err := assemblePackets(pkg.Transactions(txns))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.Bundle{Packets: packets, Header: nil}, nil
+}
+
+func (b *BundleBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *BundleBuilder) resolvePayer() (solana.PublicKey, error) {
+	if b.payer != nil {
+		return *b.payer, nil
+	}
+
+	if len(b.signers) == 0 {
+		return solana.PublicKey{}, ErrNoPayer
+	}
+
+	return b.signers[0].PublicKey(), nil
+}
+
+func (b *BundleBuilder) tipInstruction(payer solana.PublicKey, lamports uint64) (solana.Instruction, error) {
+	from := payer
+	if b.tipPayer != nil {
+		from = *b.tipPayer
+	}
+
+	if b.tipAccount == nil {
+		return b.client.GenerateTipRandomAccountInstruction(lamports, from)
+	}
+
+	tipAccount, err := b.tipAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.client.GenerateTipInstruction(lamports, from, tipAccount), nil
+}
+
+func (b *BundleBuilder) recentBlockhash(ctx context.Context) (solana.Hash, error) {
+	if b.blockhash != nil {
+		return *b.blockhash, nil
+	}
+
+	resp, err := b.client.RpcConn.GetLatestBlockhash(ctx, b.commitment)
+	if err != nil {
+		return solana.Hash{}, err
+	}
+
+	return resp.Value.Blockhash, nil
+}
+
+func (b *BundleBuilder) signer(key solana.PublicKey) *solana.PrivateKey {
+	for i := range b.signers {
+		if b.signers[i].PublicKey().Equals(key) {
+			return &b.signers[i]
+		}
+	}
+	return nil
+}
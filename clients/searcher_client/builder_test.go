@@ -0,0 +1,129 @@
+package searcher_client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BundleBuilder(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{}
+
+	payer := solana.NewWallet().PrivateKey
+	other := solana.NewWallet().PrivateKey
+	tipAccount := jito_go.MainnetTipAccounts[0]
+
+	transfer := func(from solana.PrivateKey) solana.Instruction {
+		return system.NewTransferInstruction(1, from.PublicKey(), solana.NewWallet().PublicKey()).Build()
+	}
+
+	// hasTip reports whether the transaction transfers to the tip account.
+	hasTip := func(tx *solana.Transaction) bool {
+		ok, err := tx.HasAccount(tipAccount)
+		assert.NoError(t, err)
+		return ok
+	}
+
+	t.Run("TipInLastTransaction", func(t *testing.T) {
+		txns, err := client.NewBundleBuilder().
+			AddInstructions(transfer(payer)).
+			AddInstructions(transfer(payer), transfer(other)).
+			WithSigners(payer, other).
+			WithTip(1000).
+			WithTipAccount(tipAccount).
+			WithBlockhash(solana.Hash{1}).
+			BuildTransactions(ctx)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		if !assert.Len(t, txns, 2) {
+			t.FailNow()
+		}
+		assert.False(t, hasTip(txns[0]))
+		assert.True(t, hasTip(txns[1]))
+		assert.Len(t, txns[1].Message.Instructions, 3)
+
+		for _, tx := range txns {
+			assert.Equal(t, payer.PublicKey(), tx.Message.AccountKeys[0])
+			assert.NoError(t, tx.VerifySignatures())
+		}
+	})
+
+	t.Run("TipInSeparateTransaction", func(t *testing.T) {
+		bundle, err := client.NewBundleBuilder().
+			AddInstructions(transfer(payer)).
+			WithSigners(payer).
+			WithTip(1000).
+			WithTipAccountFunc(func() (solana.PublicKey, error) { return tipAccount, nil }).
+			WithTipPlacement(TipInSeparateTransaction).
+			WithBlockhash(solana.Hash{1}).
+			Build(ctx)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		txns, err := pkg.ConvertBatchProtobufPacketToTransaction(bundle.Packets)
+		if !assert.NoError(t, err) || !assert.Len(t, txns, 2) {
+			t.FailNow()
+		}
+		assert.False(t, hasTip(txns[0]))
+		assert.True(t, hasTip(txns[1]))
+	})
+
+	t.Run("PrebuiltTransactionKeepsSignatures", func(t *testing.T) {
+		prebuilt, err := solana.NewTransaction([]solana.Instruction{transfer(other)}, solana.Hash{2}, solana.TransactionPayer(other.PublicKey()))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		_, err = prebuilt.Sign(func(solana.PublicKey) *solana.PrivateKey { return &other })
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		signature := prebuilt.Signatures[0]
+
+		txns, err := client.NewBundleBuilder().
+			AddTransaction(prebuilt).
+			WithSigners(payer).
+			WithTip(1000).
+			WithTipAccount(tipAccount).
+			WithBlockhash(solana.Hash{1}).
+			BuildTransactions(ctx)
+		if !assert.NoError(t, err) || !assert.Len(t, txns, 2) {
+			t.FailNow()
+		}
+		assert.Equal(t, signature, txns[0].Signatures[0])
+		assert.True(t, hasTip(txns[1]))
+	})
+
+	t.Run("TooManyTransactions", func(t *testing.T) {
+		builder := client.NewBundleBuilder().WithSigners(payer).WithBlockhash(solana.Hash{1})
+		for i := 0; i < MaxBundleTransactions; i++ {
+			builder.AddInstructions(transfer(payer))
+		}
+
+		_, err := builder.BuildTransactions(ctx)
+		assert.NoError(t, err)
+
+		_, err = builder.WithTip(1000).WithTipAccount(tipAccount).WithTipPlacement(TipInSeparateTransaction).BuildTransactions(ctx)
+		assert.ErrorIs(t, err, ErrTooManyTransactions)
+	})
+
+	t.Run("MissingSigner", func(t *testing.T) {
+		_, err := client.NewBundleBuilder().
+			AddInstructions(transfer(other)).
+			WithSigners(payer).
+			WithBlockhash(solana.Hash{1}).
+			BuildTransactions(ctx)
+		assert.Error(t, err)
+
+		_, err = client.NewBundleBuilder().AddInstructions(transfer(payer)).BuildTransactions(ctx)
+		assert.ErrorIs(t, err, ErrNoPayer)
+	})
+}
@@ -0,0 +1,228 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+)
+
+// MaxComputeUnitLimit is the highest compute unit limit a transaction can request.
+const MaxComputeUnitLimit = 1_400_000
+
+var (
+	ErrSimulationFailed   = errors.New("bundle simulation failed")
+	ErrNoUnitsConsumed    = errors.New("simulation did not report the units consumed")
+	ErrTooManyAccountKeys = errors.New("transaction has no room for the compute budget program")
+	ErrMissingSigner      = errors.New("no signer for the sized transaction")
+)
+
+// ComputeUnitConfig configures SizeComputeUnits, zero values fall back to sensible defaults.
+type ComputeUnitConfig struct {
+	// Margin is the fraction of the consumed units added to the limit, defaults to 0.1.
+	Margin float64
+	// UnitPrice, in micro-lamports, is set with SetComputeUnitPrice when not 0. Existing prices are kept otherwise.
+	UnitPrice uint64
+	// Signers re-sign the sized transactions, every signer of the transactions is required.
+	Signers []solana.PrivateKey
+	// Simulation is the config of the SimulateBundle call, SkipSigVerify is always set.
+	Simulation SimulateBundleConfig
+}
+
+func (c ComputeUnitConfig) withDefaults() ComputeUnitConfig {
+	if c.Margin <= 0 {
+		c.Margin = 0.1
+	}
+	return c
+}
+
+// SizeComputeUnits simulates txns and returns copies of them whose SetComputeUnitLimit is the units consumed plus a margin,
+// signed by config.Signers. The transactions are simulated with the maximum limit, so a too low limit does not fail the simulation.
+func (c *Client) SizeComputeUnits(ctx context.Context, txns []*solana.Transaction, config ComputeUnitConfig) ([]*solana.Transaction, error) {
+	config = config.withDefaults()
+
+	simulated := make([]*solana.Transaction, 0, len(txns))
+	for i, tx := range txns {
+		clone, err := cloneTransaction(tx)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		if err = SetComputeBudget(clone, MaxComputeUnitLimit, config.UnitPrice); err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+
+		// signatures are not verified, but must be present for the transaction to sanitize
+		clone.Signatures = make([]solana.Signature, clone.Message.Header.NumRequiredSignatures)
+		simulated = append(simulated, clone)
+	}
+
+	simulation := config.Simulation
+	simulation.SkipSigVerify = true
+	resp, err := c.SimulateTransactions(ctx, simulated, simulation)
+	if err != nil {
+		return nil, err
+	}
+
+	if failed := resp.Value.Summary.Failed; failed != nil {
+		return nil, fmt.Errorf("%w: transaction %d: %w", ErrSimulationFailed, failed.TxIndex, &failed.Error)
+	}
+	if len(resp.Value.TransactionResult) != len(txns) {
+		return nil, fmt.Errorf("%w: %d results for %d transactions", ErrNoUnitsConsumed, len(resp.Value.TransactionResult), len(txns))
+	}
+
+	signer := func(key solana.PublicKey) *solana.PrivateKey {
+		for i := range config.Signers {
+			if config.Signers[i].PublicKey().Equals(key) {
+				return &config.Signers[i]
+			}
+		}
+		return nil
+	}
+
+	for i, tx := range simulated {
+		consumed := resp.Value.TransactionResult[i].UnitsConsumed
+		if consumed == nil {
+			return nil, fmt.Errorf("%d: %w", i, ErrNoUnitsConsumed)
+		}
+
+		if err = SetComputeBudget(tx, ComputeUnitLimit(*consumed, config.Margin), 0); err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+
+		tx.Signatures = nil
+		if _, err = tx.Sign(signer); err != nil {
+			return nil, fmt.Errorf("%d: %w: %w", i, ErrMissingSigner, err)
+		}
+	}
+
+	return simulated, nil
+}
+
+// ComputeUnitLimit returns consumed plus margin, a fraction of it, capped to MaxComputeUnitLimit.
+func ComputeUnitLimit(consumed uint64, margin float64) uint32 {
+	limit := math.Ceil(float64(consumed) * (1 + margin))
+	return uint32(min(limit, MaxComputeUnitLimit))
+}
+
+// SetComputeBudget sets the SetComputeUnitLimit instruction of tx to limit, and its SetComputeUnitPrice to unitPrice when not 0.
+// Existing compute budget instructions are updated, missing ones are prepended. The signatures of tx are invalidated.
+func SetComputeBudget(tx *solana.Transaction, limit uint32, unitPrice uint64) error {
+	limitData, err := computebudget.NewSetComputeUnitLimitInstruction(limit).Build().Data()
+	if err != nil {
+		return err
+	}
+
+	var priceData []byte
+	if unitPrice != 0 {
+		if priceData, err = computebudget.NewSetComputeUnitPriceInstruction(unitPrice).Build().Data(); err != nil {
+			return err
+		}
+	}
+
+	// a copy has its static keys only, even when tx resolved its lookups
+	msg, err := copyMessage(&tx.Message)
+	if err != nil {
+		return err
+	}
+
+	program, err := computeBudgetProgramIndex(msg)
+	if err != nil {
+		return err
+	}
+
+	hasLimit, hasPrice := false, priceData == nil
+	for i := range msg.Instructions {
+		inst := &msg.Instructions[i]
+		if inst.ProgramIDIndex != program || len(inst.Data) == 0 {
+			continue
+		}
+
+		switch inst.Data[0] {
+		case computebudget.Instruction_SetComputeUnitLimit:
+			inst.Data, hasLimit = limitData, true
+		case computebudget.Instruction_SetComputeUnitPrice:
+			if priceData != nil {
+				inst.Data, hasPrice = priceData, true
+			}
+		}
+	}
+
+	var prepended []solana.CompiledInstruction
+	if !hasLimit {
+		prepended = append(prepended, solana.CompiledInstruction{ProgramIDIndex: program, Accounts: []uint16{}, Data: limitData})
+	}
+	if !hasPrice {
+		prepended = append(prepended, solana.CompiledInstruction{ProgramIDIndex: program, Accounts: []uint16{}, Data: priceData})
+	}
+	msg.Instructions = append(prepended, msg.Instructions...)
+
+	tx.Message = *msg
+	return nil
+}
+
+// computeBudgetProgramIndex returns the index of the compute budget program in the static keys of msg,
+// adding it as the last readonly static key when missing.
+func computeBudgetProgramIndex(msg *solana.Message) (uint16, error) {
+	static := len(msg.AccountKeys)
+	for i, key := range msg.AccountKeys {
+		if key.Equals(solana.ComputeBudget) {
+			return uint16(i), nil
+		}
+	}
+
+	if static+msg.AddressTableLookups.NumLookups() >= math.MaxUint8 {
+		return 0, ErrTooManyAccountKeys
+	}
+
+	msg.AccountKeys = append(msg.AccountKeys, solana.ComputeBudget)
+	msg.Header.NumReadonlyUnsignedAccounts++
+
+	// indexes past the static keys point into the lookups, they move by one
+	for i := range msg.Instructions {
+		inst := &msg.Instructions[i]
+		if int(inst.ProgramIDIndex) >= static {
+			inst.ProgramIDIndex++
+		}
+		for j, index := range inst.Accounts {
+			if int(index) >= static {
+				inst.Accounts[j]++
+			}
+		}
+	}
+
+	return uint16(static), nil
+}
+
+// copyMessage deep copies msg through its wire format, the copy has the static keys only in AccountKeys.
+func copyMessage(msg *solana.Message) (*solana.Message, error) {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(solana.Message)
+	if err = out.UnmarshalWithDecoder(bin.NewBinDecoder(data)); err != nil {
+		return nil, err
+	}
+	if tables := msg.GetAddressTables(); tables != nil {
+		if err = out.SetAddressTables(tables); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// cloneTransaction deep copies tx.
+func cloneTransaction(tx *solana.Transaction) (*solana.Transaction, error) {
+	msg, err := copyMessage(&tx.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &solana.Transaction{Signatures: append([]solana.Signature(nil), tx.Signatures...), Message: *msg}, nil
+}
@@ -0,0 +1,181 @@
+package searcher_client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// computeBudget returns the compute unit limit and price set by tx, 0 when unset.
+func computeBudget(t *testing.T, tx *solana.Transaction) (uint32, uint64) {
+	var (
+		limit uint32
+		price uint64
+	)
+	for _, inst := range tx.Message.Instructions {
+		if !tx.Message.AccountKeys[inst.ProgramIDIndex].Equals(solana.ComputeBudget) {
+			continue
+		}
+
+		decoded, err := computebudget.DecodeInstruction(nil, inst.Data)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		switch impl := decoded.Impl.(type) {
+		case *computebudget.SetComputeUnitLimit:
+			limit = impl.Units
+		case *computebudget.SetComputeUnitPrice:
+			price = impl.MicroLamports
+		}
+	}
+	return limit, price
+}
+
+// instructionKeys returns the keys of the accounts passed to the instructions of tx, resolving its lookups.
+func instructionKeys(t *testing.T, tx *solana.Transaction) [][]solana.PublicKey {
+	keys, err := tx.Message.GetAllKeys()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	var out [][]solana.PublicKey
+	for _, inst := range tx.Message.Instructions {
+		if keys[inst.ProgramIDIndex].Equals(solana.ComputeBudget) {
+			continue
+		}
+
+		accounts := []solana.PublicKey{keys[inst.ProgramIDIndex]}
+		for _, index := range inst.Accounts {
+			accounts = append(accounts, keys[index])
+		}
+		out = append(out, accounts)
+	}
+	return out
+}
+
+func Test_SizeComputeUnits(t *testing.T) {
+	ctx := context.Background()
+	payer := solana.NewWallet().PrivateKey
+	recipient := solana.NewWallet().PublicKey()
+	table := solana.NewWallet().PublicKey()
+
+	transfer := system.NewTransferInstruction(1, payer.PublicKey(), recipient).Build()
+	legacy, err := solana.NewTransaction(
+		[]solana.Instruction{computebudget.NewSetComputeUnitLimitInstruction(200_000).Build(), transfer},
+		solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// recipient is loaded from a lookup table, its index follows the static keys
+	v0, err := solana.NewTransaction(
+		[]solana.Instruction{transfer},
+		solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+		solana.TransactionAddressTables(map[solana.PublicKey]solana.PublicKeySlice{table: {recipient}}),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("SetComputeBudget", func(t *testing.T) {
+		tx, err := cloneTransaction(v0)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		before := instructionKeys(t, tx)
+
+		assert.NoError(t, SetComputeBudget(tx, 5000, 10))
+		limit, price := computeBudget(t, tx)
+		assert.Equal(t, uint32(5000), limit)
+		assert.Equal(t, uint64(10), price)
+		assert.Equal(t, before, instructionKeys(t, tx))
+		assert.Len(t, tx.Message.AddressTableLookups, 1)
+
+		assert.NoError(t, SetComputeBudget(tx, 6000, 0))
+		limit, price = computeBudget(t, tx)
+		assert.Equal(t, uint32(6000), limit)
+		assert.Equal(t, uint64(10), price)
+		assert.Len(t, tx.Message.Instructions, 3)
+	})
+
+	t.Run("Sized", func(t *testing.T) {
+		server, params := newSimulateBundleServer(t, func(txns int) interface{} {
+			results := make([]interface{}, txns)
+			for i := range results {
+				results[i] = map[string]interface{}{"err": nil, "unitsConsumed": 1000 * (i + 1)}
+			}
+			return map[string]interface{}{
+				"context": map[string]interface{}{"slot": 1},
+				"value":   map[string]interface{}{"summary": "succeeded", "transactionResults": results},
+			}
+		})
+
+		client := &Client{JitoRpcConn: rpc.New(server.URL)}
+		txns, err := client.SizeComputeUnits(ctx, []*solana.Transaction{legacy, v0}, ComputeUnitConfig{
+			UnitPrice: 7,
+			Signers:   []solana.PrivateKey{payer},
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		sent := <-params
+		var bundle SimulateBundleParams
+		var config map[string]interface{}
+		assert.NoError(t, json.Unmarshal(sent[0], &bundle))
+		assert.NoError(t, json.Unmarshal(sent[1], &config))
+		assert.Equal(t, true, config["skipSigVerify"])
+
+		simulated, err := base64.StdEncoding.DecodeString(bundle.EncodedTransactions[0])
+		assert.NoError(t, err)
+		tx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(simulated))
+		if assert.NoError(t, err) {
+			limit, _ := computeBudget(t, tx)
+			assert.Equal(t, uint32(MaxComputeUnitLimit), limit)
+		}
+
+		for i, tx := range txns {
+			limit, price := computeBudget(t, tx)
+			assert.Equal(t, uint32(1100*(i+1)), limit)
+			assert.Equal(t, uint64(7), price)
+			assert.NoError(t, tx.VerifySignatures())
+		}
+		assert.Equal(t, instructionKeys(t, v0), instructionKeys(t, txns[1]))
+
+		limit, price := computeBudget(t, legacy)
+		assert.Equal(t, uint32(200_000), limit, "the original transactions are left untouched")
+		assert.Zero(t, price)
+	})
+
+	t.Run("MissingSigner", func(t *testing.T) {
+		server, _ := newSimulateBundleServer(t, func(int) interface{} {
+			return map[string]interface{}{
+				"context": map[string]interface{}{"slot": 1},
+				"value": map[string]interface{}{
+					"summary":            "succeeded",
+					"transactionResults": []interface{}{map[string]interface{}{"unitsConsumed": 10}},
+				},
+			}
+		})
+
+		client := &Client{JitoRpcConn: rpc.New(server.URL)}
+		_, err := client.SizeComputeUnits(ctx, []*solana.Transaction{legacy}, ComputeUnitConfig{})
+		assert.ErrorIs(t, err, ErrMissingSigner)
+	})
+
+	t.Run("ComputeUnitLimit", func(t *testing.T) {
+		assert.Equal(t, uint32(1150), ComputeUnitLimit(1000, 0.15))
+		assert.Equal(t, uint32(MaxComputeUnitLimit), ComputeUnitLimit(MaxComputeUnitLimit, 0.1))
+	})
+}
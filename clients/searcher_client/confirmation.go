@@ -0,0 +1,148 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrTransactionFailed is returned when a transaction of the bundle landed with an error.
+var ErrTransactionFailed = errors.New("bundle transaction failed")
+
+// ConfirmationConfig configures how a bundle is confirmed, zero values fall back to DefaultConfirmationConfig.
+type ConfirmationConfig struct {
+	// Commitment is the status every transaction of the bundle must reach.
+	Commitment rpc.ConfirmationStatusType
+	// PollInterval is the delay before the first signature status poll.
+	PollInterval time.Duration
+	// MaxPollInterval caps the delay between two polls.
+	MaxPollInterval time.Duration
+	// BackoffMultiplier is applied to the delay after every poll.
+	BackoffMultiplier float64
+}
+
+var DefaultConfirmationConfig = ConfirmationConfig{
+	Commitment:        rpc.ConfirmationStatusConfirmed,
+	PollInterval:      500 * time.Millisecond,
+	MaxPollInterval:   5 * time.Second,
+	BackoffMultiplier: 1.5,
+}
+
+func (cfg ConfirmationConfig) withDefaults() ConfirmationConfig {
+	if cfg.Commitment == "" {
+		cfg.Commitment = DefaultConfirmationConfig.Commitment
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultConfirmationConfig.PollInterval
+	}
+	if cfg.MaxPollInterval < cfg.PollInterval {
+		cfg.MaxPollInterval = max(cfg.PollInterval, DefaultConfirmationConfig.MaxPollInterval)
+	}
+	if cfg.BackoffMultiplier < 1 {
+		cfg.BackoffMultiplier = DefaultConfirmationConfig.BackoffMultiplier
+	}
+	return cfg
+}
+
+// BundleConfirmation gathers what was observed while confirming a bundle.
+type BundleConfirmation struct {
+	Uuid string
+	// Statuses holds the last polled status of every transaction, in bundle order. A nil status means not found yet.
+	Statuses []*rpc.SignatureStatusesResult
+	// Outcomes holds the bundle results received from the block engine, in order.
+	Outcomes []*BundleOutcome
+}
+
+// ConfirmBundle waits until every signature of the bundle reaches the configured commitment.
+// It returns early, with the bundle's error, as soon as the block engine rejects or drops the bundle.
+// The confirmation gathered so far is returned alongside any error.
+func (c *Client) ConfirmBundle(ctx context.Context, uuid string, signatures []solana.Signature, config ConfirmationConfig) (*BundleConfirmation, error) {
+	config = config.withDefaults()
+	confirmation := &BundleConfirmation{Uuid: uuid}
+
+	handle := c.Tracker.Track(uuid)
+	defer c.Tracker.Untrack(uuid)
+	results := handle.Results()
+
+	interval := config.PollInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return confirmation, ctx.Err()
+		case result, ok := <-results:
+			if !ok {
+				// the tracker failing doesn't prevent confirming thru signature statuses
+				results = nil
+				continue
+			}
+
+			outcome := NewBundleOutcome(result)
+			confirmation.Outcomes = append(confirmation.Outcomes, outcome)
+			if outcome.Err != nil {
+				return confirmation, outcome.Err
+			}
+		case <-timer.C:
+			statuses, err := c.RpcConn.GetSignatureStatuses(ctx, false, signatures...)
+			if err != nil && !errors.Is(err, rpc.ErrNotFound) {
+				return confirmation, err
+			}
+
+			if statuses != nil {
+				confirmation.Statuses = statuses.Value
+			}
+
+			done, err := statusesReached(signatures, confirmation.Statuses, config.Commitment)
+			if err != nil || done {
+				return confirmation, err
+			}
+
+			interval = min(time.Duration(float64(interval)*config.BackoffMultiplier), config.MaxPollInterval)
+			timer.Reset(interval)
+		}
+	}
+}
+
+// statusesReached reports whether every status reached commitment, or an error if a transaction failed.
+func statusesReached(signatures []solana.Signature, statuses []*rpc.SignatureStatusesResult, commitment rpc.ConfirmationStatusType) (bool, error) {
+	if len(statuses) != len(signatures) {
+		return false, nil
+	}
+
+	done := true
+	for i, status := range statuses {
+		if status == nil {
+			done = false
+			continue
+		}
+
+		if status.Err != nil {
+			return false, fmt.Errorf("%w: %s: %v", ErrTransactionFailed, signatures[i], status.Err)
+		}
+
+		if commitmentLevel(status.ConfirmationStatus) < commitmentLevel(commitment) {
+			done = false
+		}
+	}
+
+	return done, nil
+}
+
+func commitmentLevel(status rpc.ConfirmationStatusType) int {
+	switch status {
+	case rpc.ConfirmationStatusProcessed:
+		return 1
+	case rpc.ConfirmationStatusConfirmed:
+		return 2
+	case rpc.ConfirmationStatusFinalized:
+		return 3
+	default:
+		return 0
+	}
+}
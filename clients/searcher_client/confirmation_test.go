@@ -0,0 +1,119 @@
+package searcher_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSignatureStatusesServer answers getSignatureStatuses with the status returned by statusAt for the n-th poll.
+func newSignatureStatusesServer(t *testing.T, statusAt func(poll int64) string) (*httptest.Server, *atomic.Int64) {
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Params []json.RawMessage
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+
+		var status interface{}
+		if s := statusAt(polls.Add(1)); s != "" {
+			status = map[string]interface{}{"slot": 1, "confirmations": nil, "err": nil, "confirmationStatus": s}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result": map[string]interface{}{
+				"context": map[string]interface{}{"slot": 1},
+				"value":   []interface{}{status},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &polls
+}
+
+func Test_ConfirmBundle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	config := ConfirmationConfig{
+		Commitment:      rpc.ConfirmationStatusConfirmed,
+		PollInterval:    10 * time.Millisecond,
+		MaxPollInterval: 20 * time.Millisecond,
+	}
+	signatures := []solana.Signature{{1}}
+
+	t.Run("ReachesCommitment", func(t *testing.T) {
+		server, polls := newSignatureStatusesServer(t, func(poll int64) string {
+			switch {
+			case poll < 2:
+				return ""
+			case poll < 4:
+				return string(rpc.ConfirmationStatusProcessed)
+			default:
+				return string(rpc.ConfirmationStatusConfirmed)
+			}
+		})
+
+		stream := newFakeBundleResultsStream()
+		client := &Client{RpcConn: rpc.New(server.URL), Tracker: NewBundleTracker(ctx, stream)}
+		stream.results <- acceptedResult("uuid", 10)
+
+		confirmation, err := client.ConfirmBundle(ctx, "uuid", signatures, config)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, int64(4), polls.Load())
+		assert.Equal(t, rpc.ConfirmationStatusConfirmed, confirmation.Statuses[0].ConfirmationStatus)
+		if assert.Len(t, confirmation.Outcomes, 1) {
+			assert.Equal(t, BundleStateAccepted, confirmation.Outcomes[0].State)
+		}
+	})
+
+	t.Run("StopsOnRejection", func(t *testing.T) {
+		server, polls := newSignatureStatusesServer(t, func(int64) string { return "" })
+
+		stream := newFakeBundleResultsStream()
+		client := &Client{RpcConn: rpc.New(server.URL), Tracker: NewBundleTracker(ctx, stream)}
+		stream.results <- &proto.BundleResult{
+			BundleId: "uuid",
+			Result: &proto.BundleResult_Rejected{Rejected: &proto.Rejected{
+				Reason: &proto.Rejected_SimulationFailure{SimulationFailure: &proto.SimulationFailure{TxSignature: "sig"}},
+			}},
+		}
+
+		confirmation, err := client.ConfirmBundle(ctx, "uuid", signatures, ConfirmationConfig{PollInterval: time.Minute})
+		assert.ErrorIs(t, err, ErrSimulationFailure)
+		assert.Len(t, confirmation.Outcomes, 1)
+		assert.Equal(t, int64(0), polls.Load())
+	})
+
+	t.Run("RespectsContext", func(t *testing.T) {
+		server, _ := newSignatureStatusesServer(t, func(int64) string { return "" })
+
+		stream := newFakeBundleResultsStream()
+		client := &Client{RpcConn: rpc.New(server.URL), Tracker: NewBundleTracker(ctx, stream)}
+
+		shortCtx, shortCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer shortCancel()
+
+		_, err := client.ConfirmBundle(shortCtx, "uuid", signatures, config)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
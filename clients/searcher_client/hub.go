@@ -0,0 +1,405 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pvaronik/jito-go/proto"
+)
+
+var (
+	ErrHubClosed    = errors.New("bundle results hub closed")
+	ErrSlowConsumer = errors.New("bundle results subscriber too slow")
+	ErrUnsubscribed = errors.New("bundle results subscription closed")
+)
+
+// BundleResultsStream is the receiving side of a SubscribeBundleResults stream.
+type BundleResultsStream interface {
+	Recv() (*proto.BundleResult, error)
+}
+
+// BundleResultsSubscriber opens a new SubscribeBundleResults stream, bound to ctx.
+type BundleResultsSubscriber func(ctx context.Context) (BundleResultsStream, error)
+
+// SlowConsumerPolicy is what the hub does when the buffer of a subscription is full.
+type SlowConsumerPolicy int
+
+const (
+	// DropResults drops the results the subscription has no room for.
+	DropResults SlowConsumerPolicy = iota
+	// BlockOnFull waits for the subscription to make room, delaying every other subscriber.
+	BlockOnFull
+	// DisconnectOnFull closes the subscription with ErrSlowConsumer.
+	DisconnectOnFull
+)
+
+// SubscriptionConfig configures a Subscription, zero values fall back to sensible defaults.
+type SubscriptionConfig struct {
+	// BundleIds restricts the subscription to these bundles, all bundles when empty.
+	BundleIds []string
+	// States restricts the subscription to these result types, all results when empty.
+	States []BundleState
+	// Buffer is the capacity of the results channel, defaults to 64.
+	Buffer int
+	Policy SlowConsumerPolicy
+}
+
+// HubConfig configures a BundleResultsHub, zero values fall back to sensible defaults.
+type HubConfig struct {
+	// MinBackoff is the delay before resubscribing after the stream broke, defaults to 500ms.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between resubscriptions, defaults to 30s.
+	MaxBackoff time.Duration
+}
+
+func (c HubConfig) withDefaults() HubConfig {
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff < c.MinBackoff {
+		c.MaxBackoff = max(30*time.Second, c.MinBackoff)
+	}
+	return c
+}
+
+// BundleResultsHub owns a SubscribeBundleResults stream and broadcasts every proto.BundleResult to its subscriptions.
+// The stream is reopened transparently when it breaks, until the hub context is done.
+type BundleResultsHub struct {
+	subscribe BundleResultsSubscriber
+	config    HubConfig
+
+	mu           sync.Mutex
+	subs         map[*Subscription]struct{}
+	err          error
+	closed       bool
+	resubscribes uint64
+	done         chan struct{}
+}
+
+// NewBundleResultsHub starts streaming bundle results from subscribe until ctx is done.
+func NewBundleResultsHub(ctx context.Context, subscribe BundleResultsSubscriber, config HubConfig) *BundleResultsHub {
+	h := &BundleResultsHub{
+		subscribe: subscribe,
+		config:    config.withDefaults(),
+		subs:      make(map[*Subscription]struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go h.run(ctx)
+
+	return h
+}
+
+// Subscribe registers a subscription receiving the results matching config from now on.
+func (h *BundleResultsHub) Subscribe(config SubscriptionConfig) *Subscription {
+	if config.Buffer <= 0 {
+		config.Buffer = 64
+	}
+
+	s := &Subscription{
+		hub:    h,
+		policy: config.Policy,
+		ch:     make(chan *proto.BundleResult, config.Buffer),
+		quit:   make(chan struct{}),
+	}
+
+	if len(config.BundleIds) != 0 {
+		s.bundleIds = make(map[string]struct{}, len(config.BundleIds))
+		for _, id := range config.BundleIds {
+			s.bundleIds[id] = struct{}{}
+		}
+	}
+	if len(config.States) != 0 {
+		s.states = make(map[BundleState]struct{}, len(config.States))
+		for _, state := range config.States {
+			s.states[state] = struct{}{}
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		s.close(ErrHubClosed)
+		return s
+	}
+
+	h.subs[s] = struct{}{}
+	return s
+}
+
+// Done is closed once the hub stopped.
+func (h *BundleResultsHub) Done() <-chan struct{} {
+	return h.done
+}
+
+// Err returns the last stream error, the hub keeps resubscribing until its context is done.
+func (h *BundleResultsHub) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// Resubscribes returns how many times the stream was reopened after it broke.
+func (h *BundleResultsHub) Resubscribes() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.resubscribes
+}
+
+func (h *BundleResultsHub) run(ctx context.Context) {
+	defer close(h.done)
+	defer h.close()
+
+	backoff := h.config.MinBackoff
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			h.mu.Lock()
+			h.resubscribes++
+			h.mu.Unlock()
+		}
+
+		var received bool
+		stream, err := h.subscribe(ctx)
+		if err == nil {
+			received, err = h.stream(ctx, stream)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		h.setErr(err)
+		if received {
+			backoff = h.config.MinBackoff
+		} else if attempt > 0 {
+			backoff = min(backoff*2, h.config.MaxBackoff)
+		}
+	}
+}
+
+// stream broadcasts the results of stream until it fails, reporting whether any result was received.
+func (h *BundleResultsHub) stream(ctx context.Context, stream BundleResultsStream) (bool, error) {
+	results := make(chan *proto.BundleResult)
+	errCh := make(chan error, 1)
+
+	go func() {
+		for {
+			result, err := stream.Recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	received := false
+	for {
+		select {
+		case <-ctx.Done():
+			return received, ctx.Err()
+		case err := <-errCh:
+			return received, err
+		case result := <-results:
+			received = true
+			h.broadcast(ctx, result)
+		}
+	}
+}
+
+func (h *BundleResultsHub) broadcast(ctx context.Context, result *proto.BundleResult) {
+	h.mu.Lock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for s := range h.subs {
+		subs = append(subs, s)
+	}
+	h.mu.Unlock()
+
+	state := resultState(result)
+	for _, s := range subs {
+		if s.matches(result.GetBundleId(), state) {
+			s.deliver(ctx, result)
+		}
+	}
+}
+
+func (h *BundleResultsHub) remove(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, s)
+}
+
+func (h *BundleResultsHub) setErr(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.err = err
+}
+
+func (h *BundleResultsHub) close() {
+	h.mu.Lock()
+	h.closed = true
+	subs := h.subs
+	h.subs = make(map[*Subscription]struct{})
+	h.mu.Unlock()
+
+	for s := range subs {
+		s.close(ErrHubClosed)
+	}
+}
+
+// Subscription receives the results of a BundleResultsHub matching its filter.
+// It implements BundleResultsStream, so a BundleTracker can consume it.
+type Subscription struct {
+	hub       *BundleResultsHub
+	bundleIds map[string]struct{}
+	states    map[BundleState]struct{}
+	policy    SlowConsumerPolicy
+
+	ch       chan *proto.BundleResult
+	quit     chan struct{}
+	quitOnce sync.Once
+	dropped  atomic.Uint64
+
+	// sendMu is held while delivering, so ch is never closed during a send.
+	sendMu sync.Mutex
+	mu     sync.Mutex
+	closed bool
+	err    error
+}
+
+// Results returns the channel receiving the results, closed once the subscription is closed.
+func (s *Subscription) Results() <-chan *proto.BundleResult {
+	return s.ch
+}
+
+// Recv returns the next result, or the reason the subscription was closed.
+func (s *Subscription) Recv() (*proto.BundleResult, error) {
+	result, ok := <-s.ch
+	if !ok {
+		return nil, s.Err()
+	}
+	return result, nil
+}
+
+// Err returns why the subscription was closed, nil while it is open.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Dropped returns how many results were dropped by the DropResults policy.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes, Results is closed and Recv returns ErrUnsubscribed.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+	s.close(ErrUnsubscribed)
+}
+
+func (s *Subscription) matches(bundleId string, state BundleState) bool {
+	if s.bundleIds != nil {
+		if _, ok := s.bundleIds[bundleId]; !ok {
+			return false
+		}
+	}
+	if s.states != nil {
+		if _, ok := s.states[state]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Subscription) deliver(ctx context.Context, result *proto.BundleResult) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return
+	}
+
+	switch s.policy {
+	case BlockOnFull:
+		select {
+		case s.ch <- result:
+		case <-s.quit:
+		case <-ctx.Done():
+		}
+	case DisconnectOnFull:
+		select {
+		case s.ch <- result:
+		default:
+			s.hub.remove(s)
+			s.closeLocked(ErrSlowConsumer)
+		}
+	default:
+		select {
+		case s.ch <- result:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+func (s *Subscription) close(err error) {
+	// unblock a BlockOnFull delivery before waiting for it
+	s.quitOnce.Do(func() { close(s.quit) })
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.closeLocked(err)
+}
+
+// closeLocked must be called with s.sendMu held.
+func (s *Subscription) closeLocked(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+	s.err = err
+	s.quitOnce.Do(func() { close(s.quit) })
+	close(s.ch)
+}
+
+// resultState returns the BundleState of result without decoding its rejection.
+func resultState(result *proto.BundleResult) BundleState {
+	switch result.GetResult().(type) {
+	case *proto.BundleResult_Accepted:
+		return BundleStateAccepted
+	case *proto.BundleResult_Rejected:
+		return BundleStateRejected
+	case *proto.BundleResult_Processed:
+		return BundleStateProcessed
+	case *proto.BundleResult_Finalized:
+		return BundleStateFinalized
+	case *proto.BundleResult_Dropped:
+		return BundleStateDropped
+	default:
+		return BundleStateUnknown
+	}
+}
@@ -0,0 +1,214 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newFakeHub returns a hub subscribing to the provided streams in order.
+func newFakeHub(ctx context.Context, streams ...*fakeBundleResultsStream) (*BundleResultsHub, *atomic.Int64) {
+	var subscriptions atomic.Int64
+	hub := NewBundleResultsHub(ctx, func(ctx context.Context) (BundleResultsStream, error) {
+		n := subscriptions.Add(1)
+		if int(n) > len(streams) {
+			return nil, errors.New("no more streams")
+		}
+		return streams[n-1], nil
+	}, HubConfig{MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	return hub, &subscriptions
+}
+
+// receive returns the next result of sub, failing the test after a second.
+func receive(t *testing.T, sub *Subscription) *proto.BundleResult {
+	select {
+	case result := <-sub.Results():
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("no result received")
+		return nil
+	}
+}
+
+func Test_BundleResultsHub(t *testing.T) {
+	t.Run("Broadcast", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stream := newFakeBundleResultsStream()
+		hub, _ := newFakeHub(ctx, stream)
+
+		all := hub.Subscribe(SubscriptionConfig{})
+		byId := hub.Subscribe(SubscriptionConfig{BundleIds: []string{"b"}})
+		byState := hub.Subscribe(SubscriptionConfig{States: []BundleState{BundleStateFinalized}})
+
+		stream.results <- acceptedResult("a", 1)
+		stream.results <- acceptedResult("b", 1)
+		stream.results <- finalizedResult("a")
+
+		assert.Equal(t, "a", receive(t, all).BundleId)
+		assert.Equal(t, "b", receive(t, all).BundleId)
+		assert.NotNil(t, receive(t, all).GetFinalized())
+
+		assert.Equal(t, "b", receive(t, byId).BundleId)
+		assert.Equal(t, "a", receive(t, byState).BundleId)
+
+		cancel()
+		<-hub.Done()
+		_, err := byId.Recv()
+		assert.ErrorIs(t, err, ErrHubClosed)
+	})
+
+	t.Run("Resubscribes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		first, second := newFakeBundleResultsStream(), newFakeBundleResultsStream()
+		hub, subscriptions := newFakeHub(ctx, first, second)
+		sub := hub.Subscribe(SubscriptionConfig{})
+
+		first.results <- acceptedResult("a", 1)
+		assert.Equal(t, "a", receive(t, sub).BundleId)
+
+		first.err <- errors.New("stream broken")
+		second.results <- acceptedResult("b", 1)
+		assert.Equal(t, "b", receive(t, sub).BundleId)
+		assert.Equal(t, int64(2), subscriptions.Load())
+		assert.Equal(t, uint64(1), hub.Resubscribes())
+	})
+
+	t.Run("SlowConsumerPolicies", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stream := newFakeBundleResultsStream()
+		hub, _ := newFakeHub(ctx, stream)
+
+		drop := hub.Subscribe(SubscriptionConfig{Buffer: 1, Policy: DropResults})
+		disconnect := hub.Subscribe(SubscriptionConfig{Buffer: 1, Policy: DisconnectOnFull})
+		block := hub.Subscribe(SubscriptionConfig{Buffer: 1, Policy: BlockOnFull})
+
+		for i := uint64(1); i <= 3; i++ {
+			stream.results <- acceptedResult("a", i)
+		}
+
+		for i := uint64(1); i <= 3; i++ {
+			assert.Equal(t, i, receive(t, block).GetAccepted().GetSlot())
+		}
+
+		assert.Eventually(t, func() bool { return drop.Dropped() == 2 }, time.Second, time.Millisecond)
+		assert.Equal(t, uint64(1), receive(t, drop).GetAccepted().GetSlot())
+
+		assert.Equal(t, uint64(1), receive(t, disconnect).GetAccepted().GetSlot())
+		_, err := disconnect.Recv()
+		assert.ErrorIs(t, err, ErrSlowConsumer)
+	})
+
+	t.Run("TrackerSubscription", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		first, second := newFakeBundleResultsStream(), newFakeBundleResultsStream()
+		hub, _ := newFakeHub(ctx, first, second)
+		tracker := NewBundleTracker(ctx, hub.Subscribe(SubscriptionConfig{Policy: BlockOnFull}))
+		other := hub.Subscribe(SubscriptionConfig{})
+
+		handle := tracker.Track("a")
+		first.results <- acceptedResult("a", 1)
+		first.err <- errors.New("stream broken")
+		second.results <- finalizedResult("a")
+
+		last, err := handle.Wait(ctx)
+		assert.NoError(t, err)
+		assert.NotNil(t, last.GetFinalized())
+
+		assert.Equal(t, "a", receive(t, other).BundleId)
+		other.Close()
+		for range other.Results() {
+		}
+		assert.ErrorIs(t, other.Err(), ErrUnsubscribed)
+	})
+}
+
+// closeTestServer is a local block engine authenticating every client and holding bundle results streams open.
+type closeTestServer struct {
+	proto.UnimplementedAuthServiceServer
+	proto.UnimplementedSearcherServiceServer
+
+	streamOpened chan struct{}
+	streamDone   chan struct{}
+}
+
+func (s *closeTestServer) GenerateAuthChallenge(context.Context, *proto.GenerateAuthChallengeRequest) (*proto.GenerateAuthChallengeResponse, error) {
+	return &proto.GenerateAuthChallengeResponse{Challenge: "challenge"}, nil
+}
+
+func (s *closeTestServer) GenerateAuthTokens(context.Context, *proto.GenerateAuthTokensRequest) (*proto.GenerateAuthTokensResponse, error) {
+	expiresAt := timestamppb.New(time.Now().Add(time.Hour))
+	return &proto.GenerateAuthTokensResponse{
+		AccessToken:  &proto.Token{Value: "access", ExpiresAtUtc: expiresAt},
+		RefreshToken: &proto.Token{Value: "refresh", ExpiresAtUtc: expiresAt},
+	}, nil
+}
+
+func (s *closeTestServer) RefreshAccessToken(context.Context, *proto.RefreshAccessTokenRequest) (*proto.RefreshAccessTokenResponse, error) {
+	return &proto.RefreshAccessTokenResponse{AccessToken: &proto.Token{Value: "access", ExpiresAtUtc: timestamppb.New(time.Now().Add(time.Hour))}}, nil
+}
+
+func (s *closeTestServer) SubscribeBundleResults(_ *proto.SubscribeBundleResultsRequest, stream proto.SearcherService_SubscribeBundleResultsServer) error {
+	s.streamOpened <- struct{}{}
+	<-stream.Context().Done()
+	s.streamDone <- struct{}{}
+	return stream.Context().Err()
+}
+
+func (s *closeTestServer) GetTipAccounts(context.Context, *proto.GetTipAccountsRequest) (*proto.GetTipAccountsResponse, error) {
+	return &proto.GetTipAccountsResponse{Accounts: []string{jito_go.MainnetTipAccounts[0].String()}}, nil
+}
+
+func Test_ClientClose(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	server := &closeTestServer{streamOpened: make(chan struct{}, 1), streamDone: make(chan struct{}, 1)}
+	srv := grpc.NewServer()
+	proto.RegisterAuthServiceServer(srv, server)
+	proto.RegisterSearcherServiceServer(srv, server)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	client, err := NewWithOptions(lis.Addr().String(), nil, nil, solana.NewWallet().PrivateKey, pkg.WithInsecure(), pkg.WithTimeout(5*time.Second))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	select {
+	case <-server.streamOpened:
+	case <-time.After(time.Second):
+		t.Fatal("bundle results stream not opened")
+	}
+
+	assert.NoError(t, client.Close())
+
+	for i, done := range []<-chan struct{}{client.BundleResults.Done(), client.Tracker.Done(), server.streamDone} {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("client goroutine %d still running after Close", i)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ErrInvalidMempoolConfig = errors.New("mempool subscription needs either accounts or programs")
+
+// MempoolTx is a pending transaction streamed by the mempool.
+type MempoolTx struct {
+	Tx        *solana.Transaction
+	Signature solana.Signature
+	// ServerSideTs is when the block engine generated the notification, ExpirationTime when the transaction expires.
+	ServerSideTs   time.Time
+	ExpirationTime time.Time
+	// Region is the region of the block engine the transaction was received from.
+	Region     string
+	ReceivedAt time.Time
+}
+
+// MempoolConfig configures a MempoolSubscription, zero values fall back to sensible defaults.
+type MempoolConfig struct {
+	// Accounts subscribes to the transactions write locking these accounts, Programs to the ones calling these programs.
+	// Exactly one of them must be set.
+	Accounts []string
+	Programs []string
+	// Regions are the regions to receive transactions from, the block engine's one when empty.
+	Regions []string
+	// Region labels the transactions, defaults to the current region of the block engine.
+	Region string
+	// Buffer is the capacity of the transactions channel, defaults to 1024.
+	Buffer int
+	Policy SlowConsumerPolicy
+	// DedupeSize is the number of recent signatures remembered to drop duplicated transactions, defaults to 65536.
+	DedupeSize int
+	// MinBackoff is the delay before resubscribing after the stream broke, defaults to 500ms.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between resubscriptions, defaults to 30s.
+	MaxBackoff time.Duration
+	// TerminalCodes are the gRPC status codes stopping the subscription instead of resubscribing,
+	// defaults to DefaultTerminalCodes.
+	TerminalCodes []codes.Code
+}
+
+// DefaultTerminalCodes are the gRPC status codes no resubscription can recover from.
+// Unauthenticated is not one of them: the access token is refreshed in the background and used by the next subscription.
+var DefaultTerminalCodes = []codes.Code{
+	codes.InvalidArgument,
+	codes.NotFound,
+	codes.PermissionDenied,
+	codes.FailedPrecondition,
+	codes.Unimplemented,
+}
+
+func (c MempoolConfig) withDefaults() MempoolConfig {
+	if c.Buffer <= 0 {
+		c.Buffer = 1024
+	}
+	if c.DedupeSize <= 0 {
+		c.DedupeSize = 65536
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff < c.MinBackoff {
+		c.MaxBackoff = max(30*time.Second, c.MinBackoff)
+	}
+	if c.TerminalCodes == nil {
+		c.TerminalCodes = DefaultTerminalCodes
+	}
+	return c
+}
+
+// terminal reports whether the subscription must stop after err.
+func (c MempoolConfig) terminal(err error) bool {
+	if errors.Is(err, ErrSlowConsumer) {
+		return true
+	}
+	return slices.Contains(c.TerminalCodes, status.Code(err))
+}
+
+func (c MempoolConfig) request() (*proto.MempoolSubscription, error) {
+	switch {
+	case len(c.Accounts) != 0 && len(c.Programs) == 0:
+		return &proto.MempoolSubscription{
+			Msg:     &proto.MempoolSubscription_WlaV0Sub{WlaV0Sub: &proto.WriteLockedAccountSubscriptionV0{Accounts: c.Accounts}},
+			Regions: c.Regions,
+		}, nil
+	case len(c.Programs) != 0 && len(c.Accounts) == 0:
+		return &proto.MempoolSubscription{
+			Msg:     &proto.MempoolSubscription_ProgramV0Sub{ProgramV0Sub: &proto.ProgramSubscriptionV0{Programs: c.Programs}},
+			Regions: c.Regions,
+		}, nil
+	default:
+		return nil, ErrInvalidMempoolConfig
+	}
+}
+
+// MempoolEventType is the type of a MempoolEvent.
+type MempoolEventType int
+
+const (
+	// MempoolDisconnected is emitted when the stream broke, before waiting Backoff to resubscribe.
+	MempoolDisconnected MempoolEventType = iota
+	// MempoolReconnected is emitted once the stream is reopened, after Attempt subscription attempts.
+	MempoolReconnected
+)
+
+// MempoolEvent reports a change of the stream of a MempoolSubscription.
+type MempoolEvent struct {
+	Type    MempoolEventType
+	Err     error
+	Attempt int
+	Backoff time.Duration
+	Time    time.Time
+}
+
+// MempoolSubscription delivers the mempool transactions in arrival order, without duplicates.
+// The stream is reopened with the same accounts, programs and regions when it breaks, unless the error is terminal.
+type MempoolSubscription struct {
+	config    MempoolConfig
+	subscribe func(ctx context.Context) (proto.SearcherService_SubscribeMempoolClient, error)
+	txs       chan *MempoolTx
+	events    chan MempoolEvent
+	seen      *signatureSet
+
+	dropped      atomic.Uint64
+	undecodable  atomic.Uint64
+	resubscribes atomic.Uint64
+
+	mu  sync.Mutex
+	err error
+}
+
+// SubscribeMempool opens a mempool stream and delivers its transactions until ctx is done or a terminal error,
+// the first subscription error is returned as is.
+func (c *Client) SubscribeMempool(ctx context.Context, config MempoolConfig) (*MempoolSubscription, error) {
+	config = config.withDefaults()
+
+	req, err := config.request()
+	if err != nil {
+		return nil, err
+	}
+
+	subscribe := func(ctx context.Context) (proto.SearcherService_SubscribeMempoolClient, error) {
+		return c.SearcherService.SubscribeMempool(c.Auth.AuthorizedContext(ctx), req)
+	}
+
+	stream, err := subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Region == "" {
+		if regions, err := c.SearcherService.GetRegions(c.Auth.AuthorizedContext(ctx), &proto.GetRegionsRequest{}); err == nil {
+			config.Region = regions.GetCurrentRegion()
+		}
+	}
+
+	s := &MempoolSubscription{
+		config:    config,
+		subscribe: subscribe,
+		txs:       make(chan *MempoolTx, config.Buffer),
+		events:    make(chan MempoolEvent, 16),
+		seen:      newSignatureSet(config.DedupeSize),
+	}
+
+	go s.run(ctx, stream)
+
+	return s, nil
+}
+
+// Txs returns the channel receiving the transactions, closed once the subscription stopped.
+func (s *MempoolSubscription) Txs() <-chan *MempoolTx {
+	return s.txs
+}
+
+// Events returns the channel receiving the disconnections and reconnections, closed once the subscription stopped.
+// Events are dropped while the channel is full.
+func (s *MempoolSubscription) Events() <-chan MempoolEvent {
+	return s.events
+}
+
+// Err returns why the subscription stopped, or the last stream error while it resubscribes.
+// It is ctx.Err() after a clean stop.
+func (s *MempoolSubscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Dropped returns how many transactions were dropped by the DropResults policy.
+func (s *MempoolSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Undecodable returns how many packets were skipped because they could not be decoded.
+func (s *MempoolSubscription) Undecodable() uint64 {
+	return s.undecodable.Load()
+}
+
+// Resubscribes returns how many times the stream was reopened after it broke.
+func (s *MempoolSubscription) Resubscribes() uint64 {
+	return s.resubscribes.Load()
+}
+
+func (s *MempoolSubscription) run(ctx context.Context, stream proto.SearcherService_SubscribeMempoolClient) {
+	var err error
+	defer func() {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+
+		s.setErr(err)
+		close(s.txs)
+		close(s.events)
+	}()
+
+	backoff := s.config.MinBackoff
+	for {
+		var received bool
+		if received, err = s.stream(ctx, stream); ctx.Err() != nil || s.config.terminal(err) {
+			return
+		}
+
+		s.setErr(err)
+		if received {
+			backoff = s.config.MinBackoff
+		}
+
+		if stream, err = s.resubscribe(ctx, err, &backoff); err != nil {
+			return
+		}
+	}
+}
+
+// stream delivers the notifications of stream until it fails, reporting whether any notification was received.
+func (s *MempoolSubscription) stream(ctx context.Context, stream proto.SearcherService_SubscribeMempoolClient) (bool, error) {
+	received := false
+	for {
+		notification, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+		if err = s.deliver(ctx, notification); err != nil {
+			return received, err
+		}
+	}
+}
+
+// resubscribe reopens the stream broken by cause, doubling backoff after every failed attempt.
+// It fails with ctx.Err() or a terminal error.
+func (s *MempoolSubscription) resubscribe(ctx context.Context, cause error, backoff *time.Duration) (proto.SearcherService_SubscribeMempoolClient, error) {
+	for attempt := 1; ; attempt++ {
+		delay := jitter(*backoff)
+		s.emit(MempoolEvent{Type: MempoolDisconnected, Err: cause, Attempt: attempt, Backoff: delay})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		*backoff = min(*backoff*2, s.config.MaxBackoff)
+
+		stream, err := s.subscribe(ctx)
+		if err == nil {
+			s.resubscribes.Add(1)
+			s.emit(MempoolEvent{Type: MempoolReconnected, Err: cause, Attempt: attempt})
+			return stream, nil
+		}
+
+		if ctx.Err() != nil || s.config.terminal(err) {
+			return nil, err
+		}
+		cause = err
+		s.setErr(err)
+	}
+}
+
+func (s *MempoolSubscription) emit(event MempoolEvent) {
+	event.Time = time.Now()
+	select {
+	case s.events <- event:
+	default:
+	}
+}
+
+func (s *MempoolSubscription) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// jitter returns a random delay between d/2 and d, so that clients broken together do not resubscribe together.
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// deliver sends the transactions of notification in order, failing with ErrSlowConsumer under DisconnectOnFull.
+func (s *MempoolSubscription) deliver(ctx context.Context, notification *proto.PendingTxNotification) error {
+	receivedAt := time.Now()
+
+	for _, packet := range notification.GetTransactions() {
+		tx, err := pkg.ConvertProtobufPacketToTransaction(packet)
+		if err != nil || len(tx.Signatures) == 0 {
+			s.undecodable.Add(1)
+			continue
+		}
+
+		if !s.seen.add(tx.Signatures[0]) {
+			continue
+		}
+
+		mempoolTx := &MempoolTx{
+			Tx:             tx,
+			Signature:      tx.Signatures[0],
+			ServerSideTs:   timestampTime(notification.GetServerSideTs()),
+			ExpirationTime: timestampTime(notification.GetExpirationTime()),
+			Region:         s.config.Region,
+			ReceivedAt:     receivedAt,
+		}
+
+		switch s.config.Policy {
+		case BlockOnFull:
+			select {
+			case s.txs <- mempoolTx:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case DisconnectOnFull:
+			select {
+			case s.txs <- mempoolTx:
+			default:
+				return ErrSlowConsumer
+			}
+		default:
+			select {
+			case s.txs <- mempoolTx:
+			default:
+				s.dropped.Add(1)
+			}
+		}
+	}
+
+	return nil
+}
+
+func timestampTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// signatureSet remembers the last size signatures added.
+type signatureSet struct {
+	set  map[solana.Signature]struct{}
+	ring []solana.Signature
+	next int
+}
+
+func newSignatureSet(size int) *signatureSet {
+	return &signatureSet{
+		set:  make(map[solana.Signature]struct{}, size),
+		ring: make([]solana.Signature, 0, size),
+	}
+}
+
+// add reports whether signature was not already in the set.
+func (s *signatureSet) add(signature solana.Signature) bool {
+	if _, ok := s.set[signature]; ok {
+		return false
+	}
+
+	if len(s.ring) < cap(s.ring) {
+		s.ring = append(s.ring, signature)
+	} else {
+		delete(s.set, s.ring[s.next])
+		s.ring[s.next] = signature
+		s.next = (s.next + 1) % len(s.ring)
+	}
+	s.set[signature] = struct{}{}
+
+	return true
+}
@@ -0,0 +1,338 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeMempoolStream is a mempool stream returning the notifications pushed to it, until its context is done.
+type fakeMempoolStream struct {
+	grpc.ClientStream
+	ctx           context.Context
+	notifications chan *proto.PendingTxNotification
+	err           chan error
+}
+
+func newFakeMempoolStream() *fakeMempoolStream {
+	return &fakeMempoolStream{
+		notifications: make(chan *proto.PendingTxNotification, 16),
+		err:           make(chan error, 1),
+	}
+}
+
+func (s *fakeMempoolStream) Recv() (*proto.PendingTxNotification, error) {
+	select {
+	case notification := <-s.notifications:
+		return notification, nil
+	case err := <-s.err:
+		return nil, err
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// fakeMempoolService serves the provided mempool streams in order and records the subscriptions.
+type fakeMempoolService struct {
+	*fakeSearcherService
+
+	mu       sync.Mutex
+	streams  []*fakeMempoolStream
+	requests []*proto.MempoolSubscription
+}
+
+func (f *fakeMempoolService) SubscribeMempool(ctx context.Context, in *proto.MempoolSubscription, _ ...grpc.CallOption) (proto.SearcherService_SubscribeMempoolClient, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, in)
+	if len(f.streams) == 0 {
+		return nil, errors.New("no more streams")
+	}
+
+	stream := f.streams[0]
+	f.streams = f.streams[1:]
+	stream.ctx = ctx
+	return stream, nil
+}
+
+func newMempoolClient(streams ...*fakeMempoolStream) (*Client, *fakeMempoolService) {
+	service := &fakeMempoolService{fakeSearcherService: &fakeSearcherService{region: "ny"}, streams: streams}
+	return &Client{SearcherService: service, Auth: &pkg.AuthenticationService{}}, service
+}
+
+// mempoolTx returns a signed transaction identified by seed, and its packet.
+func mempoolTx(t *testing.T, seed byte) (*solana.Transaction, *proto.Packet) {
+	payer := solana.PublicKey{seed}
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1, payer, solana.PublicKey{9}).Build()},
+		solana.Hash{1},
+		solana.TransactionPayer(payer),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	tx.Signatures = []solana.Signature{{seed}}
+
+	packet, err := pkg.ConvertTransactionToProtobufPacket(tx)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return tx, &packet
+}
+
+// receiveTx returns the next transaction of sub, failing the test after a second.
+func receiveTx(t *testing.T, sub *MempoolSubscription) *MempoolTx {
+	select {
+	case tx := <-sub.Txs():
+		return tx
+	case <-time.After(time.Second):
+		t.Fatal("no transaction received")
+		return nil
+	}
+}
+
+// waitClosed waits for sub to stop, failing the test after a second.
+func waitClosed(t *testing.T, sub *MempoolSubscription) {
+	for {
+		select {
+		case _, ok := <-sub.Txs():
+			if !ok {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscription not closed")
+		}
+	}
+}
+
+func Test_SubscribeMempool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("OrderedAndDeduplicated", func(t *testing.T) {
+		stream := newFakeMempoolStream()
+		client, service := newMempoolClient(stream)
+
+		sub, err := client.SubscribeMempool(ctx, MempoolConfig{Accounts: []string{"account"}, Regions: []string{"ny"}})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, []string{"account"}, service.requests[0].GetWlaV0Sub().GetAccounts())
+		assert.Equal(t, []string{"ny"}, service.requests[0].GetRegions())
+
+		var packets []*proto.Packet
+		var txs []*solana.Transaction
+		for i := byte(1); i <= 5; i++ {
+			tx, packet := mempoolTx(t, i)
+			txs = append(txs, tx)
+			packets = append(packets, packet)
+		}
+
+		serverTs := time.Unix(1700000000, 0).UTC()
+		stream.notifications <- &proto.PendingTxNotification{
+			ServerSideTs:   timestamppb.New(serverTs),
+			ExpirationTime: timestamppb.New(serverTs.Add(time.Second)),
+			Transactions:   append(packets[:3:3], packets[1], &proto.Packet{Data: []byte{1}}),
+		}
+		stream.notifications <- &proto.PendingTxNotification{Transactions: append([]*proto.Packet{packets[0]}, packets[3:]...)}
+
+		for i, expected := range txs {
+			tx := receiveTx(t, sub)
+			assert.Equal(t, expected.Signatures[0], tx.Signature)
+			assert.Equal(t, "ny", tx.Region)
+			assert.False(t, tx.ReceivedAt.IsZero())
+			if i < 3 {
+				assert.Equal(t, serverTs, tx.ServerSideTs)
+				assert.Equal(t, serverTs.Add(time.Second), tx.ExpirationTime)
+			} else {
+				assert.True(t, tx.ServerSideTs.IsZero())
+			}
+		}
+		assert.Equal(t, uint64(1), sub.Undecodable())
+	})
+
+	t.Run("StopsOnContext", func(t *testing.T) {
+		subCtx, subCancel := context.WithCancel(ctx)
+		client, _ := newMempoolClient(newFakeMempoolStream())
+
+		sub, err := client.SubscribeMempool(subCtx, MempoolConfig{Programs: []string{"program"}})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.NoError(t, sub.Err())
+
+		subCancel()
+		waitClosed(t, sub)
+		assert.ErrorIs(t, sub.Err(), context.Canceled)
+	})
+
+	t.Run("TerminalError", func(t *testing.T) {
+		stream := newFakeMempoolStream()
+		client, service := newMempoolClient(stream)
+
+		sub, err := client.SubscribeMempool(ctx, MempoolConfig{Programs: []string{"program"}})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		streamErr := status.Error(codes.PermissionDenied, "mempool disabled")
+		stream.err <- streamErr
+		waitClosed(t, sub)
+		assert.ErrorIs(t, sub.Err(), streamErr)
+		assert.Len(t, service.requests, 1)
+		assert.Zero(t, sub.Resubscribes())
+	})
+
+	t.Run("Resubscribes", func(t *testing.T) {
+		first, second := newFakeMempoolStream(), newFakeMempoolStream()
+		client, service := newMempoolClient(first, second)
+
+		sub, err := client.SubscribeMempool(ctx, MempoolConfig{
+			Programs:   []string{"program"},
+			Regions:    []string{"ny", "tokyo"},
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 5 * time.Millisecond,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		_, packet := mempoolTx(t, 1)
+		_, replayed := mempoolTx(t, 2)
+		first.notifications <- &proto.PendingTxNotification{Transactions: []*proto.Packet{packet}}
+		assert.Equal(t, solana.Signature{1}, receiveTx(t, sub).Signature)
+
+		streamErr := status.Error(codes.Unavailable, "connection reset")
+		first.err <- streamErr
+		second.notifications <- &proto.PendingTxNotification{Transactions: []*proto.Packet{packet, replayed}}
+		assert.Equal(t, solana.Signature{2}, receiveTx(t, sub).Signature, "the transactions seen before the reconnection are dropped")
+
+		disconnected, reconnected := <-sub.Events(), <-sub.Events()
+		assert.Equal(t, MempoolDisconnected, disconnected.Type)
+		assert.ErrorIs(t, disconnected.Err, streamErr)
+		assert.LessOrEqual(t, disconnected.Backoff, time.Millisecond)
+		assert.Equal(t, MempoolReconnected, reconnected.Type)
+		assert.Equal(t, 1, reconnected.Attempt)
+		assert.Equal(t, uint64(1), sub.Resubscribes())
+
+		service.mu.Lock()
+		if assert.Len(t, service.requests, 2) {
+			assert.Equal(t, service.requests[0], service.requests[1])
+			assert.Equal(t, []string{"program"}, service.requests[1].GetProgramV0Sub().GetPrograms())
+			assert.Equal(t, []string{"ny", "tokyo"}, service.requests[1].GetRegions())
+		}
+		service.mu.Unlock()
+
+		// the next subscription fails, it is retried with a growing backoff until a terminal error
+		second.err <- streamErr
+		assert.Eventually(t, func() bool {
+			service.mu.Lock()
+			defer service.mu.Unlock()
+			return len(service.requests) >= 4
+		}, time.Second, time.Millisecond)
+
+		// an expired token is refreshed by the auth service, so the subscription is retried with the new one
+		expired, refreshed := newFakeMempoolStream(), newFakeMempoolStream()
+		expired.err <- status.Error(codes.Unauthenticated, "token expired")
+		_, resumed := mempoolTx(t, 3)
+		refreshed.notifications <- &proto.PendingTxNotification{Transactions: []*proto.Packet{resumed}}
+
+		service.mu.Lock()
+		service.streams = []*fakeMempoolStream{expired, refreshed}
+		service.mu.Unlock()
+
+		assert.Equal(t, solana.Signature{3}, receiveTx(t, sub).Signature)
+
+		refreshed.err <- status.Error(codes.InvalidArgument, "bad program")
+		waitClosed(t, sub)
+		assert.Equal(t, codes.InvalidArgument, status.Code(sub.Err()))
+	})
+
+	t.Run("Policies", func(t *testing.T) {
+		_, first := mempoolTx(t, 1)
+		_, second := mempoolTx(t, 2)
+		notification := &proto.PendingTxNotification{Transactions: []*proto.Packet{first, second}}
+
+		stream := newFakeMempoolStream()
+		client, _ := newMempoolClient(stream)
+		dropping, err := client.SubscribeMempool(ctx, MempoolConfig{Accounts: []string{"account"}, Buffer: 1})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		stream.notifications <- notification
+		assert.Eventually(t, func() bool { return dropping.Dropped() == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, solana.Signature{1}, receiveTx(t, dropping).Signature)
+
+		stream = newFakeMempoolStream()
+		client, _ = newMempoolClient(stream)
+		disconnecting, err := client.SubscribeMempool(ctx, MempoolConfig{Accounts: []string{"account"}, Buffer: 1, Policy: DisconnectOnFull})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		stream.notifications <- notification
+		assert.Eventually(t, func() bool { return disconnecting.Err() != nil }, time.Second, time.Millisecond)
+		assert.ErrorIs(t, disconnecting.Err(), ErrSlowConsumer)
+		waitClosed(t, disconnecting)
+
+		stream = newFakeMempoolStream()
+		client, _ = newMempoolClient(stream)
+		blocking, err := client.SubscribeMempool(ctx, MempoolConfig{Accounts: []string{"account"}, Buffer: 1, Policy: BlockOnFull})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		stream.notifications <- notification
+		assert.Equal(t, solana.Signature{1}, receiveTx(t, blocking).Signature)
+		assert.Equal(t, solana.Signature{2}, receiveTx(t, blocking).Signature)
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		client, _ := newMempoolClient()
+		_, err := client.SubscribeMempool(ctx, MempoolConfig{})
+		assert.ErrorIs(t, err, ErrInvalidMempoolConfig)
+		_, err = client.SubscribeMempool(ctx, MempoolConfig{Accounts: []string{"a"}, Programs: []string{"p"}})
+		assert.ErrorIs(t, err, ErrInvalidMempoolConfig)
+	})
+
+	t.Run("LegacyPayload", func(t *testing.T) {
+		stream := newFakeMempoolStream()
+		client, _ := newMempoolClient(stream)
+
+		payload := &SubscribeProgramsMempoolTransactionsPayload{
+			Ctx:      ctx,
+			Accounts: []string{"program"},
+			TxCh:     make(chan *solana.Transaction),
+			ErrCh:    make(chan error),
+		}
+		if !assert.NoError(t, client.SubscribeProgramsMempoolTransactions(payload)) {
+			t.FailNow()
+		}
+
+		_, first := mempoolTx(t, 1)
+		_, second := mempoolTx(t, 2)
+		stream.notifications <- &proto.PendingTxNotification{Transactions: []*proto.Packet{first, second}}
+		assert.Equal(t, solana.Signature{1}, (<-payload.TxCh).Signatures[0])
+		assert.Equal(t, solana.Signature{2}, (<-payload.TxCh).Signatures[0])
+
+		streamErr := status.Error(codes.PermissionDenied, "mempool disabled")
+		stream.err <- streamErr
+		select {
+		case err := <-payload.ErrCh:
+			assert.ErrorIs(t, err, streamErr)
+		case <-time.After(time.Second):
+			t.Fatal("error not sent to the payload")
+		}
+	})
+}
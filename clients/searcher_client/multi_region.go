@@ -0,0 +1,320 @@
+package searcher_client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"google.golang.org/grpc"
+)
+
+// MainnetRegions are the jito_go.JitoEndpoints keys of the mainnet block engines.
+var MainnetRegions = []string{"AMS", "FFM", "NY", "TKY"}
+
+var ErrUnknownRegion = errors.New("unknown block engine region")
+
+// MultiRegionClient holds one searcher Client per block engine region, keyed by jito_go.JitoEndpoints key.
+type MultiRegionClient struct {
+	Clients map[string]*Client
+}
+
+// NewMultiRegion connects and authenticates to the block engine of each region concurrently, MainnetRegions when empty.
+func NewMultiRegion(regions []string, jitoRpcClient, rpcClient *rpc.Client, privateKey solana.PrivateKey, tlsConfig *tls.Config, opts ...grpc.DialOption) (*MultiRegionClient, error) {
+	if len(regions) == 0 {
+		regions = MainnetRegions
+	}
+
+	for _, region := range regions {
+		if _, ok := jito_go.JitoEndpoints[region]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRegion, region)
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		clients = make(map[string]*Client, len(regions))
+		errs    []error
+	)
+
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+
+			client, err := New(jito_go.JitoEndpoints[region].BlockEngineURL, jitoRpcClient, rpcClient, privateKey, tlsConfig, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", region, err))
+				return
+			}
+			clients[region] = client
+		}(region)
+	}
+	wg.Wait()
+
+	m := NewMultiRegionClient(clients)
+	if len(errs) != 0 {
+		m.Close()
+		return nil, errors.Join(errs...)
+	}
+
+	return m, nil
+}
+
+// NewMultiRegionClient creates a MultiRegionClient from already connected clients.
+func NewMultiRegionClient(clients map[string]*Client) *MultiRegionClient {
+	return &MultiRegionClient{Clients: clients}
+}
+
+// Regions returns the sorted regions of the client.
+func (m *MultiRegionClient) Regions() []string {
+	regions := make([]string, 0, len(m.Clients))
+	for region := range m.Clients {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Close closes the client of every region, see Client.Close.
+func (m *MultiRegionClient) Close() error {
+	var errs []error
+	for region, client := range m.Clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", region, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegionSubmission is the response of a region to a bundle.
+type RegionSubmission struct {
+	Region   string
+	Response *proto.SendBundleResponse
+	Err      error
+}
+
+// RegionOutcome is an outcome of a bundle, received from Region.
+type RegionOutcome struct {
+	*BundleOutcome
+	Region string
+}
+
+// BroadcastBundle is like SendBundle for transactions not yet assembled in a bundle.
+func (m *MultiRegionClient) BroadcastBundle(ctx context.Context, transactions []pkg.Transaction, regions []string, opts ...grpc.CallOption) (*MultiRegionBundle, error) {
+	packets, err := assemblePackets(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.SendBundle(ctx, &proto.Bundle{Packets: packets, Header: nil}, regions, opts...)
+}
+
+// SendBundle sends bundle to the provided regions concurrently, every region when empty.
+// It fails only if no region accepted the bundle, per-region errors are reported in MultiRegionBundle.Submissions.
+func (m *MultiRegionClient) SendBundle(ctx context.Context, bundle *proto.Bundle, regions []string, opts ...grpc.CallOption) (*MultiRegionBundle, error) {
+	if len(regions) == 0 {
+		regions = m.Regions()
+	}
+
+	clients := make([]*Client, 0, len(regions))
+	for _, region := range regions {
+		client, ok := m.Clients[region]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRegion, region)
+		}
+		clients = append(clients, client)
+	}
+
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("%w: no region", ErrUnknownRegion)
+	}
+
+	// the bundle is the same for every region, validate it once
+	if err := clients[0].ValidateBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	submissions := make([]RegionSubmission, len(regions))
+
+	var wg sync.WaitGroup
+	for i := range regions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			client := clients[i]
+			resp, err := client.SearcherService.SendBundle(client.Auth.AuthorizedContext(ctx), &proto.SendBundleRequest{Bundle: bundle}, opts...)
+			submissions[i] = RegionSubmission{Region: regions[i], Response: resp, Err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	b := &MultiRegionBundle{
+		Submissions: submissions,
+		// every state is reported at most once, merge never blocks on the receiver
+		outcomes: make(chan *RegionOutcome, 5),
+		handles:  make(map[string]*BundleHandle),
+	}
+
+	var errs []error
+	for i, submission := range submissions {
+		if submission.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", submission.Region, submission.Err))
+			continue
+		}
+
+		if b.BundleId == "" {
+			b.BundleId = submission.Response.Uuid
+		}
+		if tracker := clients[i].Tracker; tracker != nil {
+			b.handles[submission.Region] = tracker.Track(submission.Response.Uuid)
+		}
+	}
+
+	if b.BundleId == "" {
+		return nil, errors.Join(errs...)
+	}
+
+	go b.merge()
+
+	return b, nil
+}
+
+// MultiRegionBundle merges the results of a bundle sent to several regions into one deduplicated outcome stream.
+type MultiRegionBundle struct {
+	// BundleId is the uuid returned by the first region which accepted the bundle.
+	BundleId    string
+	Submissions []RegionSubmission
+
+	outcomes chan *RegionOutcome
+	handles  map[string]*BundleHandle
+
+	mu  sync.Mutex
+	err error
+}
+
+// Outcomes streams each state of the bundle once, from the first region reporting it. A rejection or drop is
+// only reported once every region failed, as another region may still land the bundle. The channel is closed
+// after a final outcome, or once every region stopped reporting. A region may never report a final state,
+// callers reading Outcomes instead of calling Wait must call Stop once they are done with the bundle.
+func (b *MultiRegionBundle) Outcomes() <-chan *RegionOutcome {
+	return b.outcomes
+}
+
+// Wait consumes the outcomes and returns the last one. The error is the outcome's Err if the bundle failed
+// in every region, or why results stopped before the bundle reached a final state.
+// The bundle is stopped when ctx is done before a final outcome.
+func (b *MultiRegionBundle) Wait(ctx context.Context) (*RegionOutcome, error) {
+	var last *RegionOutcome
+	for {
+		select {
+		case <-ctx.Done():
+			b.Stop()
+			return last, ctx.Err()
+		case outcome, ok := <-b.outcomes:
+			if !ok {
+				if last != nil && last.Final() {
+					return last, last.Err
+				}
+				return last, b.Err()
+			}
+			last = outcome
+		}
+	}
+}
+
+// Err returns why the outcome stream was closed before a final outcome, nil otherwise.
+func (b *MultiRegionBundle) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+// Stop releases the tracked bundle handles of every region, the outcome stream is closed once they stopped.
+func (b *MultiRegionBundle) Stop() {
+	for _, handle := range b.handles {
+		handle.Stop()
+	}
+}
+
+func (b *MultiRegionBundle) merge() {
+	defer close(b.outcomes)
+
+	received := make(chan *RegionOutcome)
+	ended := make(chan error)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	for region, handle := range b.handles {
+		go func(region string, handle *BundleHandle) {
+			for result := range handle.Results() {
+				select {
+				case received <- &RegionOutcome{BundleOutcome: NewBundleOutcome(result), Region: region}:
+				case <-quit:
+					return
+				}
+			}
+
+			select {
+			case ended <- handle.Err():
+			case <-quit:
+			}
+		}(region, handle)
+	}
+
+	seen := make(map[BundleState]struct{})
+	pending := len(b.handles)
+	var failure *RegionOutcome
+	var errs []error
+
+	for pending > 0 {
+		select {
+		case outcome := <-received:
+			if outcome.State == BundleStateRejected || outcome.State == BundleStateDropped {
+				failure = outcome
+				continue
+			}
+
+			if _, ok := seen[outcome.State]; ok {
+				continue
+			}
+			seen[outcome.State] = struct{}{}
+			b.outcomes <- outcome
+
+			if outcome.State == BundleStateFinalized {
+				b.Stop()
+				return
+			}
+		case err := <-ended:
+			pending--
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// every region stopped without finalizing the bundle, a failure is moot if a region landed it
+	if _, landed := seen[BundleStateProcessed]; failure != nil && !landed {
+		b.outcomes <- failure
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = errors.Join(errs...)
+	if b.err == nil {
+		b.err = ErrTrackerClosed
+	}
+}
@@ -0,0 +1,162 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func rejectedResult(id string, lamports uint64) *proto.BundleResult {
+	return &proto.BundleResult{BundleId: id, Result: &proto.BundleResult_Rejected{Rejected: &proto.Rejected{
+		Reason: &proto.Rejected_StateAuctionBidRejected{StateAuctionBidRejected: &proto.StateAuctionBidRejected{SimulatedBidLamports: lamports}},
+	}}}
+}
+
+// newRegionClient returns a Client backed by a fake searcher service and bundle results stream.
+func newRegionClient(ctx context.Context) (*Client, *fakeSearcherService, *fakeBundleResultsStream) {
+	service := &fakeSearcherService{}
+	stream := newFakeBundleResultsStream()
+	client := &Client{
+		SearcherService: service,
+		Tracker:         NewBundleTracker(ctx, stream),
+		Auth:            &pkg.AuthenticationService{},
+	}
+	return client, service, stream
+}
+
+func Test_MultiRegionClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newMultiRegion := func() (*MultiRegionClient, map[string]*fakeSearcherService, map[string]*fakeBundleResultsStream) {
+		clients := make(map[string]*Client)
+		services := make(map[string]*fakeSearcherService)
+		streams := make(map[string]*fakeBundleResultsStream)
+		for _, region := range MainnetRegions {
+			clients[region], services[region], streams[region] = newRegionClient(ctx)
+		}
+		return NewMultiRegionClient(clients), services, streams
+	}
+
+	t.Run("DeduplicatesOutcomes", func(t *testing.T) {
+		m, services, streams := newMultiRegion()
+
+		b, err := m.SendBundle(ctx, &proto.Bundle{}, nil)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "bundle", b.BundleId)
+		assert.Len(t, b.Submissions, len(MainnetRegions))
+		for _, service := range services {
+			assert.Len(t, service.sentBundles(), 1)
+		}
+
+		streams["AMS"].results <- rejectedResult("bundle", 1000)
+		streams["FFM"].results <- acceptedResult("bundle", 10)
+		streams["NY"].results <- acceptedResult("bundle", 10)
+		streams["NY"].results <- processedResult("bundle", 10)
+		streams["TKY"].results <- acceptedResult("bundle", 10)
+		streams["NY"].results <- finalizedResult("bundle")
+
+		var states []BundleState
+		for outcome := range b.Outcomes() {
+			states = append(states, outcome.State)
+		}
+		assert.Equal(t, []BundleState{BundleStateAccepted, BundleStateProcessed, BundleStateFinalized}, states)
+	})
+
+	t.Run("RejectedEverywhere", func(t *testing.T) {
+		m, _, streams := newMultiRegion()
+
+		b, err := m.SendBundle(ctx, &proto.Bundle{}, []string{"AMS", "TKY"})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		streams["AMS"].results <- rejectedResult("bundle", 1000)
+		streams["TKY"].results <- rejectedResult("bundle", 2000)
+
+		outcome, err := b.Wait(ctx)
+		assert.ErrorIs(t, err, ErrStateAuctionBidRejected)
+		if assert.NotNil(t, outcome) {
+			assert.Equal(t, BundleStateRejected, outcome.State)
+		}
+	})
+
+	t.Run("WaitStopsOnContext", func(t *testing.T) {
+		m, _, streams := newMultiRegion()
+
+		b, err := m.SendBundle(ctx, &proto.Bundle{}, []string{"AMS", "TKY"})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		// a region lands the bundle but never finalizes it, the other goes silent
+		streams["AMS"].results <- processedResult("bundle", 10)
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer waitCancel()
+		outcome, err := b.Wait(waitCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		if assert.NotNil(t, outcome) {
+			assert.Equal(t, BundleStateProcessed, outcome.State)
+		}
+
+		select {
+		case _, ok := <-b.Outcomes():
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			assert.Fail(t, "outcomes not closed after Wait returned")
+		}
+		assert.ErrorIs(t, b.Err(), ErrBundleUntracked)
+	})
+
+	t.Run("PartialFailure", func(t *testing.T) {
+		m, services, _ := newMultiRegion()
+		services["FFM"].sendErr = errors.New("unavailable")
+
+		b, err := m.SendBundle(ctx, &proto.Bundle{}, []string{"FFM", "NY"})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		b.Stop()
+
+		assert.Equal(t, "FFM", b.Submissions[0].Region)
+		assert.Error(t, b.Submissions[0].Err)
+		assert.NoError(t, b.Submissions[1].Err)
+
+		services["NY"].sendErr = errors.New("unavailable")
+		_, err = m.SendBundle(ctx, &proto.Bundle{}, []string{"FFM", "NY"})
+		assert.Error(t, err)
+	})
+
+	t.Run("UnknownRegion", func(t *testing.T) {
+		m, _, _ := newMultiRegion()
+
+		_, err := m.SendBundle(ctx, &proto.Bundle{}, []string{"LDN"})
+		assert.ErrorIs(t, err, ErrUnknownRegion)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		clients := make(map[string]*Client)
+		for _, region := range []string{"AMS", "NY"} {
+			regionCtx, regionCancel := context.WithCancel(ctx)
+			clients[region], _, _ = newRegionClient(regionCtx)
+			clients[region].cancel = regionCancel
+		}
+
+		assert.NoError(t, NewMultiRegionClient(clients).Close())
+		for region, client := range clients {
+			select {
+			case <-client.Tracker.Done():
+			case <-time.After(time.Second):
+				t.Fatalf("%s client still running after Close", region)
+			}
+		}
+	})
+}
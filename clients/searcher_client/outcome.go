@@ -0,0 +1,275 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// Sentinels matched with errors.Is by the typed bundle errors below.
+var (
+	ErrBundleRejected          = errors.New("bundle rejected")
+	ErrStateAuctionBidRejected = errors.New("bundle lost state auction")
+	ErrWinningBatchBidRejected = errors.New("bundle lost global auction")
+	ErrSimulationFailure       = errors.New("bundle simulation failure")
+	ErrInternalError           = errors.New("block engine internal error")
+	ErrBundleDropped           = errors.New("bundle dropped")
+	ErrRejectedDroppedBundle   = errors.New("bundle rejected as dropped")
+	ErrBlockhashExpired        = errors.New("bundle blockhash expired")
+	ErrPartiallyProcessed      = errors.New("bundle partially processed")
+	ErrNotFinalized            = errors.New("bundle not finalized")
+)
+
+// BundleState is the kind of proto.BundleResult received for a bundle.
+type BundleState int
+
+const (
+	BundleStateUnknown BundleState = iota
+	BundleStateAccepted
+	BundleStateRejected
+	BundleStateProcessed
+	BundleStateFinalized
+	BundleStateDropped
+)
+
+func (s BundleState) String() string {
+	switch s {
+	case BundleStateAccepted:
+		return "accepted"
+	case BundleStateRejected:
+		return "rejected"
+	case BundleStateProcessed:
+		return "processed"
+	case BundleStateFinalized:
+		return "finalized"
+	case BundleStateDropped:
+		return "dropped"
+	default:
+		return "unknown"
+	}
+}
+
+// BundleOutcome is the typed form of a proto.BundleResult.
+type BundleOutcome struct {
+	BundleId string
+	State    BundleState
+
+	// Slot and ValidatorIdentity are set on Accepted and Processed outcomes, BundleIndex on Processed ones.
+	Slot              uint64
+	ValidatorIdentity string
+	BundleIndex       uint64
+
+	// Err is set on Rejected and Dropped outcomes, it is one of the typed errors of this file.
+	Err error
+}
+
+// NewBundleOutcome converts a proto.BundleResult to a BundleOutcome.
+func NewBundleOutcome(result *proto.BundleResult) *BundleOutcome {
+	outcome := &BundleOutcome{BundleId: result.GetBundleId()}
+
+	switch r := result.GetResult().(type) {
+	case *proto.BundleResult_Accepted:
+		outcome.State = BundleStateAccepted
+		outcome.Slot = r.Accepted.GetSlot()
+		outcome.ValidatorIdentity = r.Accepted.GetValidatorIdentity()
+	case *proto.BundleResult_Rejected:
+		outcome.State = BundleStateRejected
+		outcome.Err = newRejectionError(result.GetBundleId(), r.Rejected)
+	case *proto.BundleResult_Processed:
+		outcome.State = BundleStateProcessed
+		outcome.Slot = r.Processed.GetSlot()
+		outcome.ValidatorIdentity = r.Processed.GetValidatorIdentity()
+		outcome.BundleIndex = r.Processed.GetBundleIndex()
+	case *proto.BundleResult_Finalized:
+		outcome.State = BundleStateFinalized
+	case *proto.BundleResult_Dropped:
+		outcome.State = BundleStateDropped
+		outcome.Err = &BundleDroppedError{BundleId: result.GetBundleId(), DroppedReason: r.Dropped.GetReason()}
+	}
+
+	return outcome
+}
+
+// Final reports whether no further outcomes are expected for the bundle.
+func (o *BundleOutcome) Final() bool {
+	return o.State == BundleStateRejected || o.State == BundleStateFinalized || o.State == BundleStateDropped
+}
+
+// WaitOutcome is like Wait but returns the typed outcome, the returned error is the outcome's Err if the bundle was rejected or dropped.
+func (h *BundleHandle) WaitOutcome(ctx context.Context) (*BundleOutcome, error) {
+	result, err := h.Wait(ctx)
+	if result == nil {
+		if err == nil {
+			err = ErrTrackerClosed
+		}
+		return nil, err
+	}
+
+	outcome := NewBundleOutcome(result)
+	if err != nil {
+		return outcome, err
+	}
+
+	return outcome, outcome.Err
+}
+
+func newRejectionError(bundleId string, rejected *proto.Rejected) error {
+	switch reason := rejected.GetReason().(type) {
+	case *proto.Rejected_StateAuctionBidRejected:
+		return &StateAuctionBidRejectedError{
+			BundleId:             bundleId,
+			AuctionId:            reason.StateAuctionBidRejected.GetAuctionId(),
+			SimulatedBidLamports: reason.StateAuctionBidRejected.GetSimulatedBidLamports(),
+			Msg:                  reason.StateAuctionBidRejected.GetMsg(),
+		}
+	case *proto.Rejected_WinningBatchBidRejected:
+		return &WinningBatchBidRejectedError{
+			BundleId:             bundleId,
+			AuctionId:            reason.WinningBatchBidRejected.GetAuctionId(),
+			SimulatedBidLamports: reason.WinningBatchBidRejected.GetSimulatedBidLamports(),
+			Msg:                  reason.WinningBatchBidRejected.GetMsg(),
+		}
+	case *proto.Rejected_SimulationFailure:
+		return &SimulationFailureError{
+			BundleId:    bundleId,
+			TxSignature: reason.SimulationFailure.GetTxSignature(),
+			Msg:         reason.SimulationFailure.GetMsg(),
+		}
+	case *proto.Rejected_InternalError:
+		return &InternalError{BundleId: bundleId, Msg: reason.InternalError.GetMsg()}
+	case *proto.Rejected_DroppedBundle:
+		return &RejectedDroppedBundleError{BundleId: bundleId, Msg: reason.DroppedBundle.GetMsg()}
+	default:
+		return fmt.Errorf("%w: unknown reason", ErrBundleRejected)
+	}
+}
+
+// StateAuctionBidRejectedError is returned when the bundle lost its state auction.
+type StateAuctionBidRejectedError struct {
+	BundleId             string
+	AuctionId            string
+	SimulatedBidLamports uint64
+	Msg                  string
+}
+
+func (e *StateAuctionBidRejectedError) Error() string {
+	return withMsg(fmt.Sprintf("bundle lost state auction, auction: %s, tip %d lamports", e.AuctionId, e.SimulatedBidLamports), e.Msg)
+}
+
+func (e *StateAuctionBidRejectedError) Is(target error) bool {
+	return target == ErrStateAuctionBidRejected || target == ErrBundleRejected
+}
+
+// WinningBatchBidRejectedError is returned when the bundle won its state auction but failed the global auction.
+type WinningBatchBidRejectedError struct {
+	BundleId             string
+	AuctionId            string
+	SimulatedBidLamports uint64
+	Msg                  string
+}
+
+func (e *WinningBatchBidRejectedError) Error() string {
+	return withMsg(fmt.Sprintf("bundle won state auction but failed global auction, auction %s, tip %d lamports", e.AuctionId, e.SimulatedBidLamports), e.Msg)
+}
+
+func (e *WinningBatchBidRejectedError) Is(target error) bool {
+	return target == ErrWinningBatchBidRejected || target == ErrBundleRejected
+}
+
+// SimulationFailureError is returned when a transaction of the bundle failed simulation.
+type SimulationFailureError struct {
+	BundleId    string
+	TxSignature string
+	Msg         string
+}
+
+func (e *SimulationFailureError) Error() string {
+	return withMsg(fmt.Sprintf("bundle simulation failure on tx %s", e.TxSignature), e.Msg)
+}
+
+func (e *SimulationFailureError) Is(target error) bool {
+	return target == ErrSimulationFailure || target == ErrBundleRejected
+}
+
+// InternalError is returned when the block engine failed to process the bundle.
+type InternalError struct {
+	BundleId string
+	Msg      string
+}
+
+func (e *InternalError) Error() string {
+	return withMsg("block engine internal error", e.Msg)
+}
+
+func (e *InternalError) Is(target error) bool {
+	return target == ErrInternalError || target == ErrBundleRejected
+}
+
+// RejectedDroppedBundleError is returned when the block engine rejected the bundle by dropping it.
+// Unlike BundleDroppedError, the bundle was never accepted, so it matches ErrBundleRejected and not ErrBundleDropped.
+type RejectedDroppedBundleError struct {
+	BundleId string
+	Msg      string
+}
+
+func (e *RejectedDroppedBundleError) Error() string {
+	return withMsg("bundle rejected as dropped", e.Msg)
+}
+
+func (e *RejectedDroppedBundleError) Is(target error) bool {
+	return target == ErrRejectedDroppedBundle || target == ErrBundleRejected
+}
+
+// BundleDroppedError is returned when the bundle was accepted but did not land, see DroppedReason.
+type BundleDroppedError struct {
+	BundleId      string
+	DroppedReason proto.DroppedReason
+}
+
+func (e *BundleDroppedError) Error() string {
+	return fmt.Sprintf("bundle dropped, reason: %s", e.DroppedReason)
+}
+
+func (e *BundleDroppedError) Is(target error) bool {
+	switch target {
+	case ErrBundleDropped:
+		return true
+	case ErrBlockhashExpired:
+		return e.DroppedReason == proto.DroppedReason_BlockhashExpired
+	case ErrPartiallyProcessed:
+		return e.DroppedReason == proto.DroppedReason_PartiallyProcessed
+	case ErrNotFinalized:
+		return e.DroppedReason == proto.DroppedReason_NotFinalized
+	default:
+		return false
+	}
+}
+
+func withMsg(s, msg string) string {
+	if msg == "" {
+		return s
+	}
+	return s + ", message: " + msg
+}
+
+func NewStateAuctionBidRejectedError(auction string, tip uint64) error {
+	return &StateAuctionBidRejectedError{AuctionId: auction, SimulatedBidLamports: tip}
+}
+
+func NewWinningBatchBidRejectedError(auction string, tip uint64) error {
+	return &WinningBatchBidRejectedError{AuctionId: auction, SimulatedBidLamports: tip}
+}
+
+func NewSimulationFailureError(tx string, message string) error {
+	return &SimulationFailureError{TxSignature: tx, Msg: message}
+}
+
+func NewInternalError(message string) error {
+	return &InternalError{Msg: message}
+}
+
+func NewDroppedBundle(message string) error {
+	return &RejectedDroppedBundleError{Msg: message}
+}
@@ -0,0 +1,104 @@
+package searcher_client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BundleOutcome(t *testing.T) {
+	msg := "not enough lamports"
+
+	t.Run("StateAuctionBidRejected", func(t *testing.T) {
+		outcome := NewBundleOutcome(&proto.BundleResult{
+			BundleId: "a",
+			Result: &proto.BundleResult_Rejected{Rejected: &proto.Rejected{
+				Reason: &proto.Rejected_StateAuctionBidRejected{StateAuctionBidRejected: &proto.StateAuctionBidRejected{
+					AuctionId:            "auction",
+					SimulatedBidLamports: 1000,
+					Msg:                  &msg,
+				}},
+			}},
+		})
+
+		assert.Equal(t, BundleStateRejected, outcome.State)
+		assert.True(t, outcome.Final())
+		assert.ErrorIs(t, outcome.Err, ErrBundleRejected)
+		assert.ErrorIs(t, outcome.Err, ErrStateAuctionBidRejected)
+		assert.False(t, errors.Is(outcome.Err, ErrWinningBatchBidRejected))
+
+		var rejection *StateAuctionBidRejectedError
+		if assert.True(t, errors.As(outcome.Err, &rejection)) {
+			assert.Equal(t, "a", rejection.BundleId)
+			assert.Equal(t, "auction", rejection.AuctionId)
+			assert.Equal(t, uint64(1000), rejection.SimulatedBidLamports)
+			assert.Equal(t, msg, rejection.Msg)
+		}
+	})
+
+	t.Run("SimulationFailure", func(t *testing.T) {
+		outcome := NewBundleOutcome(&proto.BundleResult{
+			Result: &proto.BundleResult_Rejected{Rejected: &proto.Rejected{
+				Reason: &proto.Rejected_SimulationFailure{SimulationFailure: &proto.SimulationFailure{TxSignature: "sig", Msg: &msg}},
+			}},
+		})
+
+		var failure *SimulationFailureError
+		if assert.True(t, errors.As(outcome.Err, &failure)) {
+			assert.Equal(t, "sig", failure.TxSignature)
+		}
+		assert.ErrorIs(t, outcome.Err, ErrSimulationFailure)
+		assert.Equal(t, "bundle simulation failure on tx sig, message: "+msg, outcome.Err.Error())
+		assert.Equal(t, "bundle simulation failure on tx sig", (&SimulationFailureError{TxSignature: "sig"}).Error())
+	})
+
+	t.Run("RejectedDropped", func(t *testing.T) {
+		outcome := NewBundleOutcome(&proto.BundleResult{
+			Result: &proto.BundleResult_Rejected{Rejected: &proto.Rejected{
+				Reason: &proto.Rejected_DroppedBundle{DroppedBundle: &proto.DroppedBundle{Msg: msg}},
+			}},
+		})
+
+		assert.Equal(t, BundleStateRejected, outcome.State)
+		assert.ErrorIs(t, outcome.Err, ErrRejectedDroppedBundle)
+		assert.ErrorIs(t, outcome.Err, ErrBundleRejected)
+		assert.False(t, errors.Is(outcome.Err, ErrBundleDropped))
+
+		var dropped *RejectedDroppedBundleError
+		if assert.True(t, errors.As(outcome.Err, &dropped)) {
+			assert.Equal(t, msg, dropped.Msg)
+		}
+	})
+
+	t.Run("Processed", func(t *testing.T) {
+		outcome := NewBundleOutcome(&proto.BundleResult{
+			Result: &proto.BundleResult_Processed{Processed: &proto.Processed{ValidatorIdentity: "validator", Slot: 42, BundleIndex: 3}},
+		})
+
+		assert.Equal(t, BundleStateProcessed, outcome.State)
+		assert.False(t, outcome.Final())
+		assert.NoError(t, outcome.Err)
+		assert.Equal(t, uint64(42), outcome.Slot)
+		assert.Equal(t, "validator", outcome.ValidatorIdentity)
+		assert.Equal(t, uint64(3), outcome.BundleIndex)
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		outcome := NewBundleOutcome(&proto.BundleResult{
+			Result: &proto.BundleResult_Dropped{Dropped: &proto.Dropped{Reason: proto.DroppedReason_PartiallyProcessed}},
+		})
+
+		assert.Equal(t, BundleStateDropped, outcome.State)
+		assert.ErrorIs(t, outcome.Err, ErrBundleDropped)
+		assert.ErrorIs(t, outcome.Err, ErrPartiallyProcessed)
+		assert.False(t, errors.Is(outcome.Err, ErrBlockhashExpired))
+		assert.False(t, errors.Is(outcome.Err, ErrBundleRejected))
+
+		var dropped *BundleDroppedError
+		if assert.True(t, errors.As(outcome.Err, &dropped)) {
+			assert.Equal(t, proto.DroppedReason_PartiallyProcessed, dropped.DroppedReason)
+		}
+	})
+}
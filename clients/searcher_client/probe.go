@@ -0,0 +1,321 @@
+package searcher_client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+var ErrNoReachableRegion = errors.New("no block engine region is reachable")
+
+// ProbeMethod is how RegionProber measures the latency of a block engine.
+type ProbeMethod int
+
+const (
+	// ProbeGetRegions measures the round trip of a GetRegions call on an established gRPC connection.
+	ProbeGetRegions ProbeMethod = iota
+	// ProbeTLSHandshake measures the time to open a TCP connection and complete the TLS handshake.
+	ProbeTLSHandshake
+)
+
+// ProbeConfig configures the latency probes, zero values fall back to sensible defaults.
+type ProbeConfig struct {
+	// Targets maps regions to block engine addresses, defaults to the block engines of MainnetRegions.
+	Targets map[string]string
+	Method  ProbeMethod
+	// Samples is the number of measures per region, the lowest is kept. Defaults to 3.
+	Samples int
+	// Timeout bounds the probe of each region, defaults to 2s.
+	Timeout time.Duration
+	// TLSConfig is used by ProbeTLSHandshake, and by ProbeGetRegions when DialOptions is empty.
+	TLSConfig *tls.Config
+	// DialOptions are the gRPC dial options of ProbeGetRegions, defaults to TLS transport credentials.
+	DialOptions []grpc.DialOption
+}
+
+func (c ProbeConfig) withDefaults() ProbeConfig {
+	if len(c.Targets) == 0 {
+		c.Targets = make(map[string]string, len(MainnetRegions))
+		for _, region := range MainnetRegions {
+			c.Targets[region] = jito_go.JitoEndpoints[region].BlockEngineURL
+		}
+	}
+	if c.Samples <= 0 {
+		c.Samples = 3
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 2 * time.Second
+	}
+	if c.TLSConfig == nil {
+		c.TLSConfig = &tls.Config{}
+	}
+	if len(c.DialOptions) == 0 {
+		c.DialOptions = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(c.TLSConfig))}
+	}
+	return c
+}
+
+// probeConfig returns the configuration probing the block engines the way options connect to them.
+func probeConfig(options *pkg.ClientOptions) ProbeConfig {
+	dialOptions := make([]grpc.DialOption, 0, len(options.DialOptions)+1)
+	dialOptions = append(dialOptions, grpc.WithTransportCredentials(options.TransportCredentials()))
+
+	return ProbeConfig{
+		TLSConfig:   options.TLSConfig,
+		DialOptions: append(dialOptions, options.DialOptions...),
+	}
+}
+
+// ProbeResult is the latency measured to the block engine of a region.
+type ProbeResult struct {
+	Region  string
+	Address string
+	Latency time.Duration
+	Err     error
+}
+
+// ProbeRegions measures the latency to every target concurrently and returns the results ranked,
+// reachable regions by ascending latency first.
+func ProbeRegions(ctx context.Context, config ProbeConfig) []ProbeResult {
+	config = config.withDefaults()
+
+	results := make([]ProbeResult, 0, len(config.Targets))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for region, address := range config.Targets {
+		wg.Add(1)
+		go func(region, address string) {
+			defer wg.Done()
+
+			latency, err := probe(ctx, config, address)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, ProbeResult{Region: region, Address: address, Latency: latency, Err: err})
+		}(region, address)
+	}
+	wg.Wait()
+
+	sortProbeResults(results)
+	return results
+}
+
+// FastestEndpoint probes the targets and returns the address of the fastest reachable one.
+func FastestEndpoint(ctx context.Context, config ProbeConfig) (string, error) {
+	results := ProbeRegions(ctx, config)
+	if len(results) == 0 || results[0].Err != nil {
+		errs := make([]error, 0, len(results))
+		for _, result := range results {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Region, result.Err))
+		}
+		return "", errors.Join(append([]error{ErrNoReachableRegion}, errs...)...)
+	}
+
+	return results[0].Address, nil
+}
+
+func probe(ctx context.Context, config ProbeConfig, address string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	switch config.Method {
+	case ProbeTLSHandshake:
+		return probeTLSHandshake(ctx, config, address)
+	default:
+		return probeGetRegions(ctx, config, address)
+	}
+}
+
+func probeGetRegions(ctx context.Context, config ProbeConfig, address string) (time.Duration, error) {
+	conn, err := grpc.NewClient(address, config.DialOptions...)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	service := proto.NewSearcherServiceClient(conn)
+	call := func() (time.Duration, error) {
+		start := time.Now()
+		_, err := service.GetRegions(ctx, &proto.GetRegionsRequest{})
+		return time.Since(start), reachable(err)
+	}
+
+	// the first call establishes the connection, it is not a round trip measure
+	if _, err = call(); err != nil {
+		return 0, err
+	}
+
+	best := time.Duration(0)
+	for i := 0; i < config.Samples; i++ {
+		latency, err := call()
+		if err != nil {
+			return 0, err
+		}
+		if best == 0 || latency < best {
+			best = latency
+		}
+	}
+
+	return best, nil
+}
+
+// reachable ignores the errors returned by the block engine itself, e.g. Unauthenticated, as they complete a round trip.
+func reachable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return err
+	default:
+		return nil
+	}
+}
+
+func probeTLSHandshake(ctx context.Context, config ProbeConfig, address string) (time.Duration, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, err
+	}
+
+	tlsConfig := config.TLSConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+	dialer := &tls.Dialer{Config: tlsConfig}
+
+	best := time.Duration(0)
+	for i := 0; i < config.Samples; i++ {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return 0, err
+		}
+		latency := time.Since(start)
+		conn.Close()
+
+		if best == 0 || latency < best {
+			best = latency
+		}
+	}
+
+	return best, nil
+}
+
+func sortProbeResults(results []ProbeResult) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Latency != b.Latency {
+			return a.Latency < b.Latency
+		}
+		return a.Region < b.Region
+	})
+}
+
+// RegionProber re-probes the block engine regions periodically and keeps the last ranking.
+type RegionProber struct {
+	config   ProbeConfig
+	interval time.Duration
+	onProbe  func([]ProbeResult)
+
+	mu      sync.RWMutex
+	results []ProbeResult
+	probed  chan struct{}
+}
+
+// NewRegionProber probes the regions every interval, 1 minute if not positive, until ctx is done.
+// onProbe, if not nil, receives each ranking, e.g. RegionRouter.ObserveProbes.
+func NewRegionProber(ctx context.Context, config ProbeConfig, interval time.Duration, onProbe func([]ProbeResult)) *RegionProber {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	p := &RegionProber{
+		config:   config,
+		interval: interval,
+		onProbe:  onProbe,
+		probed:   make(chan struct{}),
+	}
+
+	go p.run(ctx)
+
+	return p
+}
+
+// Probed is closed once the first ranking is available.
+func (p *RegionProber) Probed() <-chan struct{} {
+	return p.probed
+}
+
+// Results returns the last ranking, nil before the first probe completes.
+func (p *RegionProber) Results() []ProbeResult {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]ProbeResult(nil), p.results...)
+}
+
+// Fastest returns the fastest reachable region of the last ranking.
+func (p *RegionProber) Fastest() (ProbeResult, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.results) == 0 || p.results[0].Err != nil {
+		return ProbeResult{}, false
+	}
+	return p.results[0], true
+}
+
+func (p *RegionProber) run(ctx context.Context) {
+	first := true
+	for {
+		results := ProbeRegions(ctx, p.config)
+		if ctx.Err() != nil {
+			return
+		}
+
+		p.mu.Lock()
+		p.results = results
+		p.mu.Unlock()
+
+		if first {
+			close(p.probed)
+			first = false
+		}
+		if p.onProbe != nil {
+			p.onProbe(results)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+// ObserveProbes feeds the router with a ranking of RegionProber, fits NewRegionProber's onProbe.
+func (r *RegionRouter) ObserveProbes(results []ProbeResult) {
+	for _, result := range results {
+		r.Observe(result.Region, result.Latency, result.Err)
+	}
+}
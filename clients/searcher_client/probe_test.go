@@ -0,0 +1,144 @@
+package searcher_client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// delayedRegionsServer answers GetRegions after delay.
+type delayedRegionsServer struct {
+	proto.UnimplementedSearcherServiceServer
+	delay time.Duration
+}
+
+func (s *delayedRegionsServer) GetRegions(context.Context, *proto.GetRegionsRequest) (*proto.GetRegionsResponse, error) {
+	time.Sleep(s.delay)
+	return &proto.GetRegionsResponse{}, nil
+}
+
+// newDelayedRegionsServer starts a local searcher service answering GetRegions after delay, and returns its address.
+func newDelayedRegionsServer(t *testing.T, delay time.Duration) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	srv := grpc.NewServer()
+	proto.RegisterSearcherServiceServer(srv, &delayedRegionsServer{delay: delay})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func Test_ProbeRegions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	config := ProbeConfig{
+		Targets: map[string]string{
+			"AMS": newDelayedRegionsServer(t, 60*time.Millisecond),
+			"FFM": newDelayedRegionsServer(t, 5*time.Millisecond),
+			"NY":  newDelayedRegionsServer(t, 30*time.Millisecond),
+			"TKY": "127.0.0.1:1",
+		},
+		Samples:     2,
+		Timeout:     time.Second,
+		DialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	}
+
+	t.Run("Ranked", func(t *testing.T) {
+		results := ProbeRegions(ctx, config)
+		if !assert.Len(t, results, 4) {
+			t.FailNow()
+		}
+
+		var regions []string
+		for _, result := range results {
+			regions = append(regions, result.Region)
+		}
+		assert.Equal(t, []string{"FFM", "NY", "AMS", "TKY"}, regions)
+		assert.GreaterOrEqual(t, results[0].Latency, 5*time.Millisecond)
+		assert.Error(t, results[3].Err)
+
+		address, err := FastestEndpoint(ctx, config)
+		assert.NoError(t, err)
+		assert.Equal(t, config.Targets["FFM"], address)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		_, err := FastestEndpoint(ctx, ProbeConfig{
+			Targets:     map[string]string{"TKY": "127.0.0.1:1"},
+			Timeout:     time.Second,
+			DialOptions: config.DialOptions,
+		})
+		assert.ErrorIs(t, err, ErrNoReachableRegion)
+	})
+
+	t.Run("ClientOptions", func(t *testing.T) {
+		targets := map[string]string{"FFM": config.Targets["FFM"]}
+
+		insecureConfig := probeConfig(pkg.NewClientOptions(pkg.WithInsecure()))
+		insecureConfig.Targets = targets
+		address, err := FastestEndpoint(ctx, insecureConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, targets["FFM"], address)
+
+		// the local block engine does not speak TLS
+		tlsConfig := probeConfig(pkg.NewClientOptions())
+		tlsConfig.Targets = targets
+		tlsConfig.Timeout = 200 * time.Millisecond
+		_, err = FastestEndpoint(ctx, tlsConfig)
+		assert.ErrorIs(t, err, ErrNoReachableRegion)
+	})
+
+	t.Run("Prober", func(t *testing.T) {
+		proberCtx, proberCancel := context.WithCancel(ctx)
+		defer proberCancel()
+
+		clients := make(map[string]*Client)
+		for region := range config.Targets {
+			clients[region], _, _ = newRegionClient(proberCtx)
+		}
+		router := NewMultiRegionClient(clients).NewRouter(proberCtx, RouterConfig{})
+
+		prober := NewRegionProber(proberCtx, config, 10*time.Millisecond, router.ObserveProbes)
+		select {
+		case <-prober.Probed():
+		case <-ctx.Done():
+			t.Fatal("prober did not probe")
+		}
+
+		fastest, ok := prober.Fastest()
+		assert.True(t, ok)
+		assert.Equal(t, "FFM", fastest.Region)
+		assert.Equal(t, []string{"FFM", "NY", "AMS", "TKY"}, router.Ranked())
+	})
+
+	t.Run("TLSHandshake", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		results := ProbeRegions(ctx, ProbeConfig{
+			Targets:   map[string]string{"AMS": strings.TrimPrefix(srv.URL, "https://")},
+			Method:    ProbeTLSHandshake,
+			TLSConfig: &tls.Config{InsecureSkipVerify: true},
+		})
+		if assert.Len(t, results, 1) {
+			assert.NoError(t, results[0].Err)
+			assert.Greater(t, results[0].Latency, time.Duration(0))
+		}
+	})
+}
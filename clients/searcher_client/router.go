@@ -0,0 +1,258 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"google.golang.org/grpc"
+)
+
+// RegionKey returns the jito_go.JitoEndpoints key of a block engine region name, e.g. "tokyo" is "TKY".
+func RegionKey(name string) (string, bool) {
+	for key, endpoint := range jito_go.JitoEndpoints {
+		if strings.EqualFold(key, name) || (endpoint.Region != "" && strings.EqualFold(endpoint.Region, name)) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// latencySmoothing is the weight of a new sample in the latency moving average.
+const latencySmoothing = 0.3
+
+// regionHealth is what the router knows about a region.
+type regionHealth struct {
+	latency time.Duration
+	healthy bool
+	err     error
+}
+
+// RoutedSubmission is a bundle sent by a RegionRouter.
+type RoutedSubmission struct {
+	Response *proto.SendBundleResponse
+	// Region is the jito_go.JitoEndpoints key of the region the bundle was sent to.
+	Region string
+	// LeaderRegion is the block engine region of the next leader, from the cached leader schedule.
+	LeaderRegion   string
+	NextLeaderSlot uint64
+	// Fallback is set when the bundle could not be sent to the next leader's region, or the schedule was stale.
+	Fallback bool
+	// Handle receives the bundle results, nil if the region client has no tracker.
+	Handle *BundleHandle
+}
+
+// RouterConfig configures a RegionRouter, zero values fall back to sensible defaults.
+type RouterConfig struct {
+	// Scheduler provides the leader schedule, defaults to a LeaderScheduler polling the first region.
+	Scheduler *LeaderScheduler
+	// MaxScheduleAge is the age past which the leader schedule is stale and bundles are routed by latency only,
+	// defaults to 1s.
+	MaxScheduleAge time.Duration
+}
+
+func (c RouterConfig) withDefaults() RouterConfig {
+	if c.MaxScheduleAge <= 0 {
+		c.MaxScheduleAge = time.Second
+	}
+	return c
+}
+
+// RegionRouter sends each bundle to the block engine of the next leader's region, falling back to the
+// lowest-latency healthy region. The leader schedule is polled in the background, so sending does not wait on it.
+// Latencies are measured on the router calls, Observe feeds external measures.
+type RegionRouter struct {
+	multi     *MultiRegionClient
+	scheduler *LeaderScheduler
+	config    RouterConfig
+
+	mu      sync.Mutex
+	regions map[string]*regionHealth
+}
+
+// NewRouter creates a RegionRouter over the regions of the client, polling the leader schedule until ctx is done.
+func (m *MultiRegionClient) NewRouter(ctx context.Context, config RouterConfig) *RegionRouter {
+	r := &RegionRouter{
+		multi:     m,
+		scheduler: config.Scheduler,
+		config:    config.withDefaults(),
+		regions:   make(map[string]*regionHealth, len(m.Clients)),
+	}
+
+	for region := range m.Clients {
+		r.regions[region] = &regionHealth{healthy: true}
+	}
+
+	if regions := m.Regions(); r.scheduler == nil && len(regions) != 0 {
+		r.scheduler = m.Clients[regions[0]].NewLeaderScheduler(ctx, SchedulerConfig{})
+	}
+
+	return r
+}
+
+// LeaderSchedule returns the cached leader schedule, false when it is unknown or stale.
+func (r *RegionRouter) LeaderSchedule() (LeaderSchedule, bool) {
+	if r.scheduler == nil {
+		return LeaderSchedule{}, false
+	}
+
+	schedule, ok := r.scheduler.LeaderSchedule()
+	if !ok || time.Since(schedule.UpdatedAt) > r.config.MaxScheduleAge {
+		return LeaderSchedule{}, false
+	}
+	return schedule, true
+}
+
+// Observe records a latency measure, or a failure when err is set, of region.
+func (r *RegionRouter) Observe(region string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health, ok := r.regions[region]
+	if !ok {
+		return
+	}
+
+	health.err = err
+	health.healthy = err == nil
+	if err != nil {
+		return
+	}
+
+	if health.latency == 0 {
+		health.latency = latency
+	} else {
+		health.latency = time.Duration(float64(health.latency)*(1-latencySmoothing) + float64(latency)*latencySmoothing)
+	}
+}
+
+// Ranked returns the regions by preference: healthy ones by ascending latency, unmeasured ones, then unhealthy ones.
+func (r *RegionRouter) Ranked() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rank := func(h *regionHealth) int {
+		switch {
+		case !h.healthy:
+			return 2
+		case h.latency == 0:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	regions := make([]string, 0, len(r.regions))
+	for region := range r.regions {
+		regions = append(regions, region)
+	}
+
+	sort.Slice(regions, func(i, j int) bool {
+		a, b := r.regions[regions[i]], r.regions[regions[j]]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.latency != b.latency {
+			return a.latency < b.latency
+		}
+		return regions[i] < regions[j]
+	})
+
+	return regions
+}
+
+// Fastest returns the healthy region with the lowest latency.
+func (r *RegionRouter) Fastest() (string, bool) {
+	ranked := r.Ranked()
+	if len(ranked) == 0 || !r.healthy(ranked[0]) {
+		return "", false
+	}
+	return ranked[0], true
+}
+
+// BroadcastBundle is like SendBundle for transactions not yet assembled in a bundle.
+func (r *RegionRouter) BroadcastBundle(ctx context.Context, transactions []pkg.Transaction, opts ...grpc.CallOption) (*RoutedSubmission, error) {
+	packets, err := assemblePackets(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.SendBundle(ctx, &proto.Bundle{Packets: packets, Header: nil}, opts...)
+}
+
+// SendBundle sends bundle to the block engine of the next leader's region. It falls back to the other regions,
+// by preference, when that region is unknown, unhealthy or fails to accept the bundle, or the schedule is stale.
+func (r *RegionRouter) SendBundle(ctx context.Context, bundle *proto.Bundle, opts ...grpc.CallOption) (*RoutedSubmission, error) {
+	ranked := r.Ranked()
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("%w: no region", ErrUnknownRegion)
+	}
+
+	if err := r.multi.Clients[ranked[0]].ValidateBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	submission := &RoutedSubmission{}
+
+	order := ranked
+	if schedule, ok := r.LeaderSchedule(); ok {
+		submission.LeaderRegion = schedule.NextLeaderRegion
+		submission.NextLeaderSlot = schedule.NextLeaderSlot
+
+		if key, ok := RegionKey(submission.LeaderRegion); ok && r.healthy(key) {
+			order = append([]string{key}, without(ranked, key)...)
+		}
+	}
+
+	var errs []error
+	for i, region := range order {
+		client := r.multi.Clients[region]
+
+		start := time.Now()
+		resp, err := client.SearcherService.SendBundle(client.Auth.AuthorizedContext(ctx), &proto.SendBundleRequest{Bundle: bundle}, opts...)
+		r.Observe(region, time.Since(start), err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", region, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		submission.Response = resp
+		submission.Region = region
+		submission.Fallback = i > 0 || !strings.EqualFold(jito_go.JitoEndpoints[region].Region, submission.LeaderRegion)
+		if client.Tracker != nil {
+			submission.Handle = client.Tracker.Track(resp.Uuid)
+		}
+
+		return submission, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (r *RegionRouter) healthy(region string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health, ok := r.regions[region]
+	return ok && health.healthy
+}
+
+func without(regions []string, region string) []string {
+	out := make([]string, 0, len(regions))
+	for _, r := range regions {
+		if r != region {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RegionRouter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// newRouter returns a router whose cached schedule has the next leader in leaderRegion.
+	newRouter := func(t *testing.T, routerCtx context.Context, leaderRegion string, config RouterConfig) (*RegionRouter, map[string]*fakeSearcherService) {
+		clients := make(map[string]*Client)
+		services := make(map[string]*fakeSearcherService)
+		for _, region := range MainnetRegions {
+			clients[region], services[region], _ = newRegionClient(ctx)
+			services[region].setNextLeader(100, 102, leaderRegion)
+		}
+
+		router := NewMultiRegionClient(clients).NewRouter(routerCtx, config)
+		router.Observe("AMS", 40*time.Millisecond, nil)
+		router.Observe("FFM", 10*time.Millisecond, nil)
+		router.Observe("NY", 30*time.Millisecond, nil)
+		router.Observe("TKY", 20*time.Millisecond, nil)
+
+		if !assert.Eventually(t, func() bool {
+			_, ok := router.LeaderSchedule()
+			return ok
+		}, time.Second, 5*time.Millisecond) {
+			t.FailNow()
+		}
+
+		return router, services
+	}
+
+	leaderCalls := func(services map[string]*fakeSearcherService) int {
+		var calls int
+		for _, service := range services {
+			service.mu.Lock()
+			calls += service.nextLeaderCalls
+			service.mu.Unlock()
+		}
+		return calls
+	}
+
+	t.Run("NextLeaderRegion", func(t *testing.T) {
+		router, services := newRouter(t, ctx, "tokyo", RouterConfig{})
+
+		submission, err := router.SendBundle(ctx, &proto.Bundle{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer submission.Handle.Stop()
+
+		assert.Equal(t, "TKY", submission.Region)
+		assert.Equal(t, "tokyo", submission.LeaderRegion)
+		assert.Equal(t, uint64(102), submission.NextLeaderSlot)
+		assert.False(t, submission.Fallback)
+		assert.NotNil(t, submission.Handle)
+		assert.Len(t, services["TKY"].sentBundles(), 1)
+	})
+
+	t.Run("UnknownLeaderRegion", func(t *testing.T) {
+		router, _ := newRouter(t, ctx, "london", RouterConfig{})
+
+		submission, err := router.SendBundle(ctx, &proto.Bundle{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer submission.Handle.Stop()
+
+		assert.Equal(t, "FFM", submission.Region)
+		assert.True(t, submission.Fallback)
+	})
+
+	t.Run("LeaderRegionFails", func(t *testing.T) {
+		router, services := newRouter(t, ctx, "ny", RouterConfig{})
+		services["NY"].sendErr = errors.New("unavailable")
+
+		submission, err := router.SendBundle(ctx, &proto.Bundle{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer submission.Handle.Stop()
+
+		assert.Equal(t, "FFM", submission.Region)
+		assert.True(t, submission.Fallback)
+		assert.Equal(t, []string{"FFM", "TKY", "AMS", "NY"}, router.Ranked())
+
+		// unhealthy regions are skipped even when leading
+		submission, err = router.SendBundle(ctx, &proto.Bundle{})
+		if assert.NoError(t, err) {
+			submission.Handle.Stop()
+			assert.Equal(t, "FFM", submission.Region)
+		}
+	})
+
+	t.Run("StaleSchedule", func(t *testing.T) {
+		schedulerCtx, schedulerCancel := context.WithCancel(ctx)
+		router, services := newRouter(t, schedulerCtx, "tokyo", RouterConfig{MaxScheduleAge: 50 * time.Millisecond})
+
+		// the schedule is no longer polled, so it goes stale
+		schedulerCancel()
+		assert.Eventually(t, func() bool {
+			_, ok := router.LeaderSchedule()
+			return !ok
+		}, time.Second, 5*time.Millisecond)
+
+		calls := leaderCalls(services)
+		submission, err := router.SendBundle(ctx, &proto.Bundle{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer submission.Handle.Stop()
+
+		assert.Equal(t, "FFM", submission.Region)
+		assert.Empty(t, submission.LeaderRegion)
+		assert.True(t, submission.Fallback)
+		assert.Equal(t, calls, leaderCalls(services), "sending must not poll the leader schedule")
+	})
+
+	t.Run("RegionKey", func(t *testing.T) {
+		for name, expected := range map[string]string{"amsterdam": "AMS", "Frankfurt": "FFM", "ny": "NY", "TKY": "TKY"} {
+			key, ok := RegionKey(name)
+			assert.True(t, ok)
+			assert.Equal(t, expected, key)
+		}
+
+		_, ok := RegionKey("london")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,360 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pvaronik/jito-go/proto"
+	"google.golang.org/grpc"
+)
+
+var (
+	ErrSchedulerClosed = errors.New("leader scheduler closed")
+	ErrNoLeaderInTime  = errors.New("no jito leader came up before the bundle expired")
+)
+
+// SchedulerConfig configures a LeaderScheduler, zero values fall back to sensible defaults.
+type SchedulerConfig struct {
+	// LeaderWindow releases queued bundles once a Jito leader is at most this many slots away, defaults to 2.
+	LeaderWindow uint64
+	// PollInterval is how often GetNextScheduledLeader is called, defaults to 400ms i.e. a slot.
+	PollInterval time.Duration
+	// ConnectedLeadersInterval is how often the GetConnectedLeaders slot lists are refreshed, defaults to 1 minute.
+	ConnectedLeadersInterval time.Duration
+	// MaxWait fails bundles queued for longer, defaults to 30s to leave room within the blockhash lifetime.
+	MaxWait time.Duration
+	// Regions restricts the leaders to these block engine regions, all regions when empty.
+	Regions []string
+}
+
+func (c SchedulerConfig) withDefaults() SchedulerConfig {
+	if c.LeaderWindow == 0 {
+		c.LeaderWindow = 2
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 400 * time.Millisecond
+	}
+	if c.ConnectedLeadersInterval <= 0 {
+		c.ConnectedLeadersInterval = time.Minute
+	}
+	if c.MaxWait <= 0 {
+		c.MaxWait = 30 * time.Second
+	}
+	return c
+}
+
+// LeaderSchedule is the view of upcoming Jito leaders the scheduler releases bundles on.
+type LeaderSchedule struct {
+	CurrentSlot        uint64
+	NextLeaderSlot     uint64
+	NextLeaderIdentity string
+	NextLeaderRegion   string
+	// UpdatedAt is when the schedule was polled.
+	UpdatedAt time.Time
+}
+
+// SlotsUntilLeader returns how many slots are left before the next Jito leader, 0 if it is leading.
+func (s LeaderSchedule) SlotsUntilLeader() uint64 {
+	if s.NextLeaderSlot <= s.CurrentSlot {
+		return 0
+	}
+	return s.NextLeaderSlot - s.CurrentSlot
+}
+
+// ScheduledSubmission is the result of a bundle released by a LeaderScheduler.
+type ScheduledSubmission struct {
+	Response *proto.SendBundleResponse
+	// ReleasedSlot is the slot the bundle was sent at.
+	ReleasedSlot uint64
+	// ExpectedSlot is the slot of the Jito leader expected to land the bundle.
+	ExpectedSlot   uint64
+	LeaderIdentity string
+	LeaderRegion   string
+	QueuedFor      time.Duration
+}
+
+// ScheduledBundle is a bundle waiting in a LeaderScheduler.
+type ScheduledBundle struct {
+	Bundle *proto.Bundle
+
+	opts     []grpc.CallOption
+	queuedAt time.Time
+	done     chan struct{}
+	result   *ScheduledSubmission
+	err      error
+}
+
+// Wait blocks until the bundle is sent or failed, or ctx is done.
+func (b *ScheduledBundle) Wait(ctx context.Context) (*ScheduledSubmission, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.done:
+		return b.result, b.err
+	}
+}
+
+// Done is closed once the bundle is sent or failed.
+func (b *ScheduledBundle) Done() <-chan struct{} {
+	return b.done
+}
+
+func (b *ScheduledBundle) finish(result *ScheduledSubmission, err error) {
+	b.result, b.err = result, err
+	close(b.done)
+}
+
+// LeaderScheduler queues bundles and sends them when a Jito leader is within SchedulerConfig.LeaderWindow slots.
+type LeaderScheduler struct {
+	client *Client
+	config SchedulerConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	queue     []*ScheduledBundle
+	schedule  LeaderSchedule
+	hasLeader bool
+	// leaderSlots are the sorted slots of the connected Jito leaders during the current epoch.
+	leaderSlots []uint64
+	leaders     map[uint64]string
+	// region is the block engine region the connected leaders are connected to.
+	region string
+	err    error
+	closed bool
+}
+
+// NewLeaderScheduler creates a LeaderScheduler polling the block engine until ctx is done.
+func (c *Client) NewLeaderScheduler(ctx context.Context, config SchedulerConfig) *LeaderScheduler {
+	s := &LeaderScheduler{
+		client: c,
+		config: config.withDefaults(),
+		now:    time.Now,
+	}
+
+	go s.run(ctx)
+
+	return s
+}
+
+// Schedule queues bundle until a Jito leader comes up. Bundles failing validation are failed right away.
+func (s *LeaderScheduler) Schedule(bundle *proto.Bundle, opts ...grpc.CallOption) *ScheduledBundle {
+	scheduled := &ScheduledBundle{
+		Bundle:   bundle,
+		opts:     opts,
+		queuedAt: s.now(),
+		done:     make(chan struct{}),
+	}
+
+	if err := s.client.ValidateBundle(bundle); err != nil {
+		scheduled.finish(nil, err)
+		return scheduled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		scheduled.finish(nil, ErrSchedulerClosed)
+		return scheduled
+	}
+
+	s.queue = append(s.queue, scheduled)
+	return scheduled
+}
+
+// LeaderSchedule returns the last known leader schedule, false until the block engine was polled successfully.
+func (s *LeaderScheduler) LeaderSchedule() (LeaderSchedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedule, s.hasLeader
+}
+
+// ExpectedSlot returns the slot of the Jito leader a bundle sent now is expected to land in.
+func (s *LeaderScheduler) ExpectedSlot() (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedule.NextLeaderSlot, s.hasLeader
+}
+
+// Pending returns the number of queued bundles.
+func (s *LeaderScheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// Err returns the last error polling the block engine, nil if the last poll succeeded.
+func (s *LeaderScheduler) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *LeaderScheduler) run(ctx context.Context) {
+	defer s.close(ctx)
+
+	var refreshedAt time.Time
+	for {
+		if refreshedAt.IsZero() || time.Since(refreshedAt) >= s.config.ConnectedLeadersInterval {
+			if err := s.refreshConnectedLeaders(ctx); err == nil {
+				refreshedAt = time.Now()
+			}
+		}
+
+		s.poll(ctx)
+		s.release(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.config.PollInterval):
+		}
+	}
+}
+
+func (s *LeaderScheduler) refreshConnectedLeaders(ctx context.Context) error {
+	resp, err := s.client.SearcherService.GetConnectedLeaders(s.client.Auth.AuthorizedContext(ctx), &proto.ConnectedLeadersRequest{})
+	if err != nil {
+		s.setErr(err)
+		return err
+	}
+
+	leaders := make(map[uint64]string)
+	slots := make([]uint64, 0)
+	for identity, list := range resp.GetConnectedValidators() {
+		for _, slot := range list.GetSlots() {
+			leaders[slot] = identity
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+
+	// GetConnectedLeaders only covers the region of the block engine we are connected to
+	var region string
+	if regions, err := s.client.SearcherService.GetRegions(s.client.Auth.AuthorizedContext(ctx), &proto.GetRegionsRequest{}); err == nil {
+		region = regions.GetCurrentRegion()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leaderSlots = slots
+	s.leaders = leaders
+	s.region = region
+
+	return nil
+}
+
+func (s *LeaderScheduler) poll(ctx context.Context) {
+	resp, err := s.client.SearcherService.GetNextScheduledLeader(
+		s.client.Auth.AuthorizedContext(ctx),
+		&proto.NextScheduledLeaderRequest{Regions: s.config.Regions},
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+	if err != nil {
+		// never release bundles on a stale schedule
+		s.hasLeader = false
+		return
+	}
+
+	schedule := LeaderSchedule{
+		CurrentSlot:        resp.GetCurrentSlot(),
+		NextLeaderSlot:     resp.GetNextLeaderSlot(),
+		NextLeaderIdentity: resp.GetNextLeaderIdentity(),
+		NextLeaderRegion:   resp.GetNextLeaderRegion(),
+		UpdatedAt:          s.now(),
+	}
+
+	// the connected leaders slot lists may know of a closer leader, e.g. while one is leading,
+	// unless their region is filtered out
+	i := sort.Search(len(s.leaderSlots), func(i int) bool { return s.leaderSlots[i] >= schedule.CurrentSlot })
+	if i < len(s.leaderSlots) && s.regionAllowed(s.region) {
+		if slot := s.leaderSlots[i]; schedule.NextLeaderSlot < schedule.CurrentSlot || slot < schedule.NextLeaderSlot {
+			schedule.NextLeaderSlot = slot
+			schedule.NextLeaderIdentity = s.leaders[slot]
+			schedule.NextLeaderRegion = s.region
+		}
+	}
+
+	s.schedule = schedule
+	s.hasLeader = schedule.NextLeaderSlot >= schedule.CurrentSlot
+}
+
+// regionAllowed returns whether region passes SchedulerConfig.Regions, an unknown region only passes without filter.
+func (s *LeaderScheduler) regionAllowed(region string) bool {
+	return len(s.config.Regions) == 0 || slices.Contains(s.config.Regions, region)
+}
+
+// release sends the queued bundles when a leader is within the window, and fails the expired ones.
+func (s *LeaderScheduler) release(ctx context.Context) {
+	s.mu.Lock()
+	now := s.now()
+	inWindow := s.hasLeader && s.schedule.SlotsUntilLeader() <= s.config.LeaderWindow
+	schedule := s.schedule
+
+	var ready, expired []*ScheduledBundle
+	queue := s.queue[:0]
+	for _, b := range s.queue {
+		switch {
+		case inWindow:
+			ready = append(ready, b)
+		case now.Sub(b.queuedAt) >= s.config.MaxWait:
+			expired = append(expired, b)
+		default:
+			queue = append(queue, b)
+		}
+	}
+	s.queue = queue
+	s.mu.Unlock()
+
+	for _, b := range expired {
+		b.finish(nil, ErrNoLeaderInTime)
+	}
+
+	for _, b := range ready {
+		resp, err := s.client.sendBundle(s.client.Auth.AuthorizedContext(ctx), b.Bundle, b.opts...)
+		if err != nil {
+			b.finish(nil, err)
+			continue
+		}
+
+		b.finish(&ScheduledSubmission{
+			Response:       resp,
+			ReleasedSlot:   schedule.CurrentSlot,
+			ExpectedSlot:   max(schedule.NextLeaderSlot, schedule.CurrentSlot),
+			LeaderIdentity: schedule.NextLeaderIdentity,
+			LeaderRegion:   schedule.NextLeaderRegion,
+			QueuedFor:      now.Sub(b.queuedAt),
+		}, nil)
+	}
+}
+
+func (s *LeaderScheduler) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *LeaderScheduler) close(ctx context.Context) {
+	s.mu.Lock()
+	queue := s.queue
+	s.queue = nil
+	s.closed = true
+	s.mu.Unlock()
+
+	err := ctx.Err()
+	if err == nil {
+		err = ErrSchedulerClosed
+	}
+
+	for _, b := range queue {
+		b.finish(nil, err)
+	}
+}
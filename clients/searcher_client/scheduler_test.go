@@ -0,0 +1,188 @@
+package searcher_client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// fakeSearcherService is a proto.SearcherServiceClient serving scripted leader schedules and recording sent bundles.
+type fakeSearcherService struct {
+	proto.SearcherServiceClient
+
+	mu              sync.Mutex
+	region          string
+	nextLeader      *proto.NextScheduledLeaderResponse
+	nextLeaderCalls int
+	connected       map[string]*proto.SlotList
+	sent            []*proto.Bundle
+	sendErr         error
+	uuid            func(bundle *proto.Bundle) string
+}
+
+func (f *fakeSearcherService) setNextLeader(current, next uint64, region string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextLeader = &proto.NextScheduledLeaderResponse{CurrentSlot: current, NextLeaderSlot: next, NextLeaderIdentity: "leader", NextLeaderRegion: region}
+}
+
+func (f *fakeSearcherService) sentBundles() []*proto.Bundle {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.Bundle(nil), f.sent...)
+}
+
+func (f *fakeSearcherService) GetNextScheduledLeader(context.Context, *proto.NextScheduledLeaderRequest, ...grpc.CallOption) (*proto.NextScheduledLeaderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextLeaderCalls++
+	return f.nextLeader, nil
+}
+
+func (f *fakeSearcherService) GetConnectedLeaders(context.Context, *proto.ConnectedLeadersRequest, ...grpc.CallOption) (*proto.ConnectedLeadersResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &proto.ConnectedLeadersResponse{ConnectedValidators: f.connected}, nil
+}
+
+func (f *fakeSearcherService) GetRegions(context.Context, *proto.GetRegionsRequest, ...grpc.CallOption) (*proto.GetRegionsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &proto.GetRegionsResponse{CurrentRegion: f.region}, nil
+}
+
+func (f *fakeSearcherService) SendBundle(_ context.Context, in *proto.SendBundleRequest, _ ...grpc.CallOption) (*proto.SendBundleResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.sendErr != nil {
+		return nil, f.sendErr
+	}
+
+	f.sent = append(f.sent, in.Bundle)
+
+	uuid := "bundle"
+	if f.uuid != nil {
+		uuid = f.uuid(in.Bundle)
+	}
+	return &proto.SendBundleResponse{Uuid: uuid}, nil
+}
+
+func Test_LeaderScheduler(t *testing.T) {
+	config := SchedulerConfig{LeaderWindow: 2, PollInterval: 5 * time.Millisecond, MaxWait: time.Minute}
+
+	newScheduler := func(t *testing.T, service *fakeSearcherService, config SchedulerConfig) *LeaderScheduler {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		client := &Client{SearcherService: service, Auth: &pkg.AuthenticationService{}}
+		return client.NewLeaderScheduler(ctx, config)
+	}
+
+	t.Run("ReleasedWhenLeaderInWindow", func(t *testing.T) {
+		service := &fakeSearcherService{}
+		service.setNextLeader(100, 110, "ny")
+		s := newScheduler(t, service, config)
+
+		scheduled := s.Schedule(&proto.Bundle{})
+		time.Sleep(30 * time.Millisecond)
+		assert.Empty(t, service.sentBundles())
+		assert.Equal(t, 1, s.Pending())
+
+		service.setNextLeader(108, 110, "ny")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		submission, err := scheduled.Wait(ctx)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Len(t, service.sentBundles(), 1)
+		assert.Equal(t, "bundle", submission.Response.Uuid)
+		assert.Equal(t, uint64(108), submission.ReleasedSlot)
+		assert.Equal(t, uint64(110), submission.ExpectedSlot)
+		assert.Equal(t, "ny", submission.LeaderRegion)
+	})
+
+	t.Run("ConnectedLeaderSlots", func(t *testing.T) {
+		service := &fakeSearcherService{
+			region:    "tokyo",
+			connected: map[string]*proto.SlotList{"closer": {Slots: []uint64{90, 201, 202}}},
+		}
+		service.setNextLeader(200, 300, "ny")
+		s := newScheduler(t, service, config)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		submission, err := s.Schedule(&proto.Bundle{}).Wait(ctx)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, uint64(201), submission.ExpectedSlot)
+		assert.Equal(t, "closer", submission.LeaderIdentity)
+		assert.Equal(t, "tokyo", submission.LeaderRegion)
+
+		slot, ok := s.ExpectedSlot()
+		assert.True(t, ok)
+		assert.Equal(t, uint64(201), slot)
+	})
+
+	t.Run("ConnectedLeaderSlotsFilteredRegion", func(t *testing.T) {
+		service := &fakeSearcherService{
+			region:    "tokyo",
+			connected: map[string]*proto.SlotList{"closer": {Slots: []uint64{201, 202}}},
+		}
+		service.setNextLeader(200, 300, "ny")
+		filtered := config
+		filtered.Regions = []string{"ny"}
+		s := newScheduler(t, service, filtered)
+
+		assert.Eventually(t, func() bool {
+			_, ok := s.LeaderSchedule()
+			return ok
+		}, time.Second, 5*time.Millisecond)
+
+		schedule, _ := s.LeaderSchedule()
+		assert.Equal(t, uint64(300), schedule.NextLeaderSlot)
+		assert.Equal(t, "ny", schedule.NextLeaderRegion)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		service := &fakeSearcherService{}
+		service.setNextLeader(100, 500, "ny")
+		s := newScheduler(t, service, SchedulerConfig{PollInterval: 5 * time.Millisecond, MaxWait: 20 * time.Millisecond})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := s.Schedule(&proto.Bundle{}).Wait(ctx)
+		assert.ErrorIs(t, err, ErrNoLeaderInTime)
+		assert.Empty(t, service.sentBundles())
+	})
+
+	t.Run("FailedOnCancel", func(t *testing.T) {
+		service := &fakeSearcherService{}
+		service.setNextLeader(100, 500, "ny")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s := (&Client{SearcherService: service, Auth: &pkg.AuthenticationService{}}).NewLeaderScheduler(ctx, config)
+		scheduled := s.Schedule(&proto.Bundle{})
+		cancel()
+
+		waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+		defer waitCancel()
+		_, err := scheduled.Wait(waitCtx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		<-time.After(10 * time.Millisecond)
+		_, err = s.Schedule(&proto.Bundle{}).Wait(waitCtx)
+		assert.ErrorIs(t, err, ErrSchedulerClosed)
+	})
+}
@@ -3,21 +3,16 @@ package searcher_client
 import (
 	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
-	"github.com/blocto/solana-go-sdk/types"
-	"github.com/mr-tron/base58"
-	"math/big"
 	"math/rand"
-	"time"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go"
 	"github.com/pvaronik/jito-go/pkg"
 	"github.com/pvaronik/jito-go/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 type Client struct {
@@ -25,47 +20,114 @@ type Client struct {
 	RpcConn     *rpc.Client
 	JitoRpcConn *rpc.Client
 
-	SearcherService       proto.SearcherServiceClient
+	SearcherService proto.SearcherServiceClient
+	// SubscribeBundleStream is the first stream of BundleResults, nil with pkg.WithLazyAuth.
+	//
+	// Deprecated: the stream is owned by BundleResults, calling Recv on it steals results from every subscriber.
+	// Use BundleResults.Subscribe instead.
 	SubscribeBundleStream proto.SearcherService_SubscribeBundleResultsClient
+	// BundleResults broadcasts the bundle results to Tracker and any other subscriber.
+	BundleResults *BundleResultsHub
+	Tracker       *BundleTracker
+	// Validator checks bundles before they are sent, nil disables pre-flight validation.
+	Validator *BundleValidator
+	// TipAccounts caches the tip accounts used by GenerateTipRandomAccountInstruction.
+	TipAccounts *TipAccountProvider
 
 	Auth *pkg.AuthenticationService
 
 	ErrChan chan error
+
+	// cancel stops the background goroutines of the client, see Close.
+	cancel context.CancelFunc
 }
 
 // New creates a new Searcher Client instance.
+// An empty grpcDialURL connects to the mainnet block engine with the lowest latency, see FastestEndpoint.
 func New(grpcDialURL string, jitoRpcClient, rpcClient *rpc.Client, privateKey solana.PrivateKey, tlsConfig *tls.Config, opts ...grpc.DialOption) (*Client, error) {
-	if tlsConfig != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	return NewWithOptions(grpcDialURL, jitoRpcClient, rpcClient, privateKey, pkg.WithTLSConfig(tlsConfig), pkg.WithDialOptions(opts...))
+}
+
+// NewWithOptions creates a new Searcher Client instance configured by opts, see pkg.ClientOptions.
+// An empty grpcDialURL connects to the mainnet block engine with the lowest latency, see FastestEndpoint.
+func NewWithOptions(grpcDialURL string, jitoRpcClient, rpcClient *rpc.Client, privateKey solana.PrivateKey, opts ...pkg.ClientOption) (*Client, error) {
+	options := pkg.NewClientOptions(opts...)
+
+	// the background goroutines run until Close, or until the context of the options is done
+	ctx, cancel := context.WithCancel(options.Ctx)
+	options.Ctx = ctx
+
+	if grpcDialURL == "" {
+		probeCtx, probeCancel := options.ConstructionContext()
+		var err error
+		grpcDialURL, err = FastestEndpoint(probeCtx, probeConfig(options))
+		probeCancel()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
 	}
 
-	conn, err := pkg.CreateAndObserveGRPCConn(context.Background(), grpcDialURL, opts...)
+	conn, authService, err := options.NewAuthenticationService(grpcDialURL, privateKey, proto.Role_SEARCHER)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	searcherService := proto.NewSearcherServiceClient(conn)
-	authService := pkg.NewAuthenticationService(conn, privateKey)
-	if err = authService.AuthenticateAndRefresh(proto.Role_SEARCHER); err != nil {
-		return nil, err
-	}
 
-	subBundleRes, err := searcherService.SubscribeBundleResults(authService.GrpcCtx, &proto.SubscribeBundleResultsRequest{})
-	if err != nil {
-		return nil, err
+	// the hub starts with the stream opened here, so subscription failures are reported by the constructor,
+	// unless authentication is lazy
+	var subBundleRes proto.SearcherService_SubscribeBundleResultsClient
+	if !options.LazyAuth {
+		subBundleRes, err = searcherService.SubscribeBundleResults(authService.AuthorizedContext(options.Ctx), &proto.SubscribeBundleResultsRequest{})
+		if err != nil {
+			cancel()
+			conn.Close()
+			return nil, err
+		}
 	}
 
-	return &Client{
+	first := subBundleRes
+	hub := NewBundleResultsHub(options.Ctx, func(ctx context.Context) (BundleResultsStream, error) {
+		if first != nil {
+			stream := first
+			first = nil
+			return stream, nil
+		}
+		return searcherService.SubscribeBundleResults(authService.AuthorizedContext(ctx), &proto.SubscribeBundleResultsRequest{})
+	}, HubConfig{})
+
+	client := &Client{
 		GrpcConn:              conn,
 		RpcConn:               rpcClient,
 		JitoRpcConn:           jitoRpcClient,
 		SearcherService:       searcherService,
 		SubscribeBundleStream: subBundleRes,
+		BundleResults:         hub,
+		Tracker:               NewBundleTracker(options.Ctx, hub.Subscribe(SubscriptionConfig{Policy: BlockOnFull})),
+		Validator:             NewBundleValidator(jito_go.MainnetTipAccounts, jito_go.TestnetTipAccounts),
 		Auth:                  authService,
 		ErrChan:               make(chan error),
-	}, nil
+		cancel:                cancel,
+	}
+
+	client.TipAccounts = client.NewTipAccountProvider(options.Ctx, TipAccountProviderConfig{Network: NetworkFromURL(grpcDialURL)})
+	client.Validator.TipAccountsFunc = client.TipAccounts.Accounts
+
+	return client, nil
+}
+
+// Close stops the bundle results subscription, the tracker, the tip accounts refresh and the access token refresh,
+// then closes the gRPC connection.
+func (c *Client) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.GrpcConn == nil {
+		return nil
+	}
+	return c.GrpcConn.Close()
 }
 
 // NewMempoolStreamAccount creates a new mempool subscription on specific Solana accounts.
@@ -109,99 +171,51 @@ type SubscribeProgramsMempoolTransactionsPayload struct {
 }
 
 // SubscribeAccountsMempoolTransactions subscribes to the mempool transactions of the provided accounts.
+// Transactions are sent to payload.TxCh in arrival order, the error ending the subscription to payload.ErrCh.
+// See SubscribeMempool for the transactions metadata and the slow consumer policies.
 func (c *Client) SubscribeAccountsMempoolTransactions(payload *SubscribeAccountsMempoolTransactionsPayload) error {
-	sub, err := c.NewMempoolStreamAccount(payload.Accounts, payload.Regions)
+	sub, err := c.SubscribeMempool(payload.Ctx, MempoolConfig{Accounts: payload.Accounts, Regions: payload.Regions, Policy: BlockOnFull})
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				if err = c.SubscribeAccountsMempoolTransactions(payload); err != nil {
-					payload.ErrCh <- fmt.Errorf("SubscribeAccountsMempoolTransactions: recovered from panic but unable to restart sub stream: %w", err)
-					return
-				}
-			}
-		}()
-		for {
-			select {
-			case <-payload.Ctx.Done():
-				return
-			default:
-				var receipt *proto.PendingTxNotification
-				receipt, err = sub.Recv()
-				if err != nil {
-					c.ErrChan <- fmt.Errorf("SubscribeAccountsMempoolTransactions: failed to receive mempool notification: %w", err)
-					continue
-				}
-
-				for _, transaction := range receipt.Transactions {
-					go func(transaction *proto.Packet) {
-						var tx *solana.Transaction
-						tx, err = pkg.ConvertProtobufPacketToTransaction(transaction)
-						if err != nil {
-							c.ErrChan <- fmt.Errorf("SubscribeAccountsMempoolTransactions: failed to convert protobuf packet to transaction: %w", err)
-							return
-						}
-
-						payload.TxCh <- tx
-					}(transaction)
-				}
-			}
-		}
-	}()
+	go forwardMempoolTransactions(payload.Ctx, sub, payload.TxCh, payload.ErrCh, "SubscribeAccountsMempoolTransactions")
 
 	return nil
 }
 
 // SubscribeProgramsMempoolTransactions subscribes to the mempool transactions of the provided programs.
+// Transactions are sent to payload.TxCh in arrival order, the error ending the subscription to payload.ErrCh.
+// See SubscribeMempool for the transactions metadata and the slow consumer policies.
 func (c *Client) SubscribeProgramsMempoolTransactions(payload *SubscribeProgramsMempoolTransactionsPayload) error {
-	sub, err := c.NewMempoolStreamProgram(payload.Accounts, payload.Regions)
+	sub, err := c.SubscribeMempool(payload.Ctx, MempoolConfig{Programs: payload.Accounts, Regions: payload.Regions, Policy: BlockOnFull})
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				if err = c.SubscribeProgramsMempoolTransactions(payload); err != nil {
-					payload.ErrCh <- fmt.Errorf("SubscribeProgramsMempoolTransactions: recovered from panic but unable to restart sub stream: %w", err)
-					return
-				}
-			}
-		}()
-		for {
-			select {
-			case <-payload.Ctx.Done():
-				return
-			default:
-				var receipt *proto.PendingTxNotification
-				receipt, err = sub.Recv()
-				if err != nil {
-					c.ErrChan <- fmt.Errorf("SubscribeProgramsMempoolTransactions: failed to receive mempool notification: %w", err)
-					continue
-				}
-
-				for _, transaction := range receipt.Transactions {
-					go func(transaction *proto.Packet) {
-						var tx *solana.Transaction
-						tx, err = pkg.ConvertProtobufPacketToTransaction(transaction)
-						if err != nil {
-							c.ErrChan <- fmt.Errorf("SubscribeProgramsMempoolTransactions: failed to convert protobuf packet to transaction: %w", err)
-							return
-						}
-
-						payload.TxCh <- tx
-					}(transaction)
-				}
-			}
-		}
-	}()
+	go forwardMempoolTransactions(payload.Ctx, sub, payload.TxCh, payload.ErrCh, "SubscribeProgramsMempoolTransactions")
 
 	return nil
 }
 
+// forwardMempoolTransactions sends the transactions of sub to txCh, then the error ending sub to errCh unless ctx is done.
+func forwardMempoolTransactions(ctx context.Context, sub *MempoolSubscription, txCh chan *solana.Transaction, errCh chan error, name string) {
+	for tx := range sub.Txs() {
+		select {
+		case txCh <- tx.Tx:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if err := sub.Err(); err != nil && ctx.Err() == nil && errCh != nil {
+		select {
+		case errCh <- fmt.Errorf("%s: failed to receive mempool notification: %w", name, err):
+		case <-ctx.Done():
+		}
+	}
+}
+
 func (c *Client) GetRegions(opts ...grpc.CallOption) (*proto.GetRegionsResponse, error) {
 	return c.SearcherService.GetRegions(c.Auth.GrpcCtx, &proto.GetRegionsRequest{}, opts...)
 }
@@ -225,6 +239,10 @@ func (c *Client) GetRandomTipAccount(opts ...grpc.CallOption) (string, error) {
 		return "", err
 	}
 
+	if len(resp.Accounts) == 0 {
+		return "", ErrNoTipAccount
+	}
+
 	return resp.Accounts[rand.Intn(len(resp.Accounts))], nil
 }
 
@@ -238,177 +256,83 @@ func (c *Client) NewBundleSubscriptionResults(opts ...grpc.CallOption) (proto.Se
 }
 
 // BroadcastBundle sends a bundle of transactions on chain thru Jito.
-func (c *Client) BroadcastBundle(transactions []types.Transaction, opts ...grpc.CallOption) (*proto.SendBundleResponse, error) {
+func (c *Client) BroadcastBundle(transactions []pkg.Transaction, opts ...grpc.CallOption) (*proto.SendBundleResponse, error) {
 	packets, err := assemblePackets(transactions)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.SearcherService.SendBundle(c.Auth.GrpcCtx, &proto.SendBundleRequest{Bundle: &proto.Bundle{Packets: packets, Header: nil}}, opts...)
+	return c.SendBundle(&proto.Bundle{Packets: packets, Header: nil}, opts...)
 }
 
-// BroadcastBundleWithConfirmation sends a bundle of transactions on chain thru Jito BlockEngine and waits for its confirmation.
-func (c *Client) BroadcastBundleWithConfirmation(ctx context.Context, transactions []types.Transaction, opts ...grpc.CallOption) (*proto.SendBundleResponse, error) {
-	bloctoBundleSignatures := pkg.BatchExtractSigFromTx(transactions)
-
-	bundleSignatures := make([]solana.Signature, 0, len(bloctoBundleSignatures))
-	for _, sig := range bloctoBundleSignatures {
-		bundleSignatures = append(bundleSignatures, solana.MustSignatureFromBase58(base58.Encode(sig)))
-	}
-	resp, err := c.BroadcastBundle(transactions, opts...)
-	if err != nil {
-		return nil, err
-	}
-
-	retries := 5
-	for i := 0; i < retries; i++ {
-		select {
-		case <-c.Auth.GrpcCtx.Done():
-			return nil, c.Auth.GrpcCtx.Err()
-		default:
-
-			// waiting 5s to check bundle result
-			time.Sleep(5 * time.Second)
-
-			var bundleResult *proto.BundleResult
-			bundleResult, err = c.SubscribeBundleStream.Recv()
-			if err != nil {
-				continue
-			}
-
-			if err = c.handleBundleResult(bundleResult); err != nil {
-				return nil, err
-			}
-
-			var start = time.Now()
-			var statuses *rpc.GetSignatureStatusesResult
-
-			for {
-				statuses, err = c.RpcConn.GetSignatureStatuses(ctx, false, bundleSignatures...)
-				if err != nil {
-					return nil, err
-				}
-				ready := true
-
-				for _, status := range statuses.Value {
-					if status == nil {
-						ready = false
-						break
-					}
-				}
-
-				if ready {
-					break
-				}
-
-				if time.Since(start) > 15*time.Second {
-					return nil, errors.New("operation timed out after 15 seconds")
-				} else {
-					time.Sleep(1 * time.Second)
-				}
-			}
-
-			for _, status := range statuses.Value {
-				if status.ConfirmationStatus != rpc.ConfirmationStatusProcessed && status.ConfirmationStatus != rpc.ConfirmationStatusConfirmed {
-					return nil, errors.New("searcher service did not provide bundle status in time")
-				}
-			}
-
-			return resp, nil
-		}
-	}
-
-	return nil, fmt.Errorf("error waiting for max retries exceeded")
+// SendBundle sends an assembled bundle thru Jito, see AssembleBundle and BundleBuilder.
+func (c *Client) SendBundle(bundle *proto.Bundle, opts ...grpc.CallOption) (*proto.SendBundleResponse, error) {
+	return c.sendBundle(c.Auth.GrpcCtx, bundle, opts...)
 }
 
-func (c *Client) handleBundleResult(bundleResult *proto.BundleResult) error {
-	switch bundleResult.Result.(type) {
-	case *proto.BundleResult_Accepted:
-		break
-	case *proto.BundleResult_Rejected:
-		rejected := bundleResult.Result.(*proto.BundleResult_Rejected)
-		switch rejected.Rejected.Reason.(type) {
-		case *proto.Rejected_SimulationFailure:
-			rejection := rejected.Rejected.GetSimulationFailure()
-			return NewSimulationFailureError(rejection.TxSignature, rejection.GetMsg())
-		case *proto.Rejected_StateAuctionBidRejected:
-			rejection := rejected.Rejected.GetStateAuctionBidRejected()
-			return NewStateAuctionBidRejectedError(rejection.AuctionId, rejection.SimulatedBidLamports)
-		case *proto.Rejected_WinningBatchBidRejected:
-			rejection := rejected.Rejected.GetWinningBatchBidRejected()
-			return NewWinningBatchBidRejectedError(rejection.AuctionId, rejection.SimulatedBidLamports)
-		case *proto.Rejected_InternalError:
-			rejection := rejected.Rejected.GetInternalError()
-			return NewInternalError(rejection.Msg)
-		case *proto.Rejected_DroppedBundle:
-			rejection := rejected.Rejected.GetDroppedBundle()
-			return NewDroppedBundle(rejection.Msg)
-		default:
-			return nil
-		}
+// ValidateBundle runs the pre-flight checks of Validator on bundle, without sending it.
+func (c *Client) ValidateBundle(bundle *proto.Bundle) error {
+	if c.Validator == nil {
+		return nil
 	}
-	return nil
-}
 
-type SimulateBundleConfig struct {
-	PreExecutionAccountsConfigs  []ExecutionAccounts `json:"preExecutionAccountsConfigs"`
-	PostExecutionAccountsConfigs []ExecutionAccounts `json:"postExecutionAccountsConfigs"`
+	return c.Validator.Validate(bundle)
 }
 
-type ExecutionAccounts struct {
-	Encoding  string   `json:"encoding"`
-	Addresses []string `json:"addresses"`
-}
+func (c *Client) sendBundle(ctx context.Context, bundle *proto.Bundle, opts ...grpc.CallOption) (*proto.SendBundleResponse, error) {
+	if err := c.ValidateBundle(bundle); err != nil {
+		return nil, err
+	}
 
-type SimulateBundleParams struct {
-	EncodedTransactions []string `json:"encodedTransactions"`
+	return c.SearcherService.SendBundle(ctx, &proto.SendBundleRequest{Bundle: bundle}, opts...)
 }
 
-type SimulatedBundleResponse struct {
-	Context interface{}                   `json:"context"`
-	Value   SimulatedBundleResponseStruct `json:"value"`
-}
+// BroadcastTrackedBundle sends a bundle of transactions thru Jito and returns a handle receiving its results.
+func (c *Client) BroadcastTrackedBundle(transactions []pkg.Transaction, opts ...grpc.CallOption) (*BundleHandle, error) {
+	resp, err := c.BroadcastBundle(transactions, opts...)
+	if err != nil {
+		return nil, err
+	}
 
-type SimulatedBundleResponseStruct struct {
-	Summary           interface{}         `json:"summary"`
-	TransactionResult []TransactionResult `json:"transactionResults"`
+	return c.Tracker.Track(resp.Uuid), nil
 }
 
-type TransactionResult struct {
-	Err                   interface{} `json:"err,omitempty"`
-	Logs                  []string    `json:"logs,omitempty"`
-	PreExecutionAccounts  []Account   `json:"preExecutionAccounts,omitempty"`
-	PostExecutionAccounts []Account   `json:"postExecutionAccounts,omitempty"`
-	UnitsConsumed         *int        `json:"unitsConsumed,omitempty"`
-	ReturnData            *ReturnData `json:"returnData,omitempty"`
-}
+// BroadcastBundleWithConfirmation sends a bundle of transactions on chain thru Jito BlockEngine and waits for its confirmation
+// with DefaultConfirmationConfig.
+func (c *Client) BroadcastBundleWithConfirmation(ctx context.Context, transactions []pkg.Transaction, opts ...grpc.CallOption) (*proto.SendBundleResponse, error) {
+	confirmation, err := c.BroadcastBundleAndConfirm(ctx, transactions, DefaultConfirmationConfig, opts...)
+	if err != nil {
+		return nil, err
+	}
 
-type Account struct {
-	Executable bool     `json:"executable"`
-	Owner      string   `json:"owner"`
-	Lamports   int      `json:"lamports"`
-	Data       []string `json:"data"`
-	RentEpoch  *big.Int `json:"rentEpoch,omitempty"`
+	return &proto.SendBundleResponse{Uuid: confirmation.Uuid}, nil
 }
 
-type ReturnData struct {
-	ProgramId string    `json:"programId"`
-	Data      [2]string `json:"data"`
-}
+// BroadcastBundleAndConfirm sends a bundle of transactions on chain thru Jito BlockEngine and confirms it, see ConfirmBundle.
+func (c *Client) BroadcastBundleAndConfirm(ctx context.Context, transactions []pkg.Transaction, config ConfirmationConfig, opts ...grpc.CallOption) (*BundleConfirmation, error) {
+	packets, err := assemblePackets(transactions)
+	if err != nil {
+		return nil, err
+	}
 
-// SimulateBundle is an RPC method that simulates a Jito bundle – exclusively available to Jito-Solana validator.
-func (c *Client) SimulateBundle(ctx context.Context, bundleParams SimulateBundleParams, simulationConfigs SimulateBundleConfig) (*SimulatedBundleResponse, error) {
-	out := new(SimulatedBundleResponse)
+	bundleSignatures, err := pkg.BatchExtractSigFromPackets(packets)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(bundleParams.EncodedTransactions) != len(simulationConfigs.PreExecutionAccountsConfigs) {
-		return nil, errors.New("pre/post execution account config length must match bundle length")
+	resp, err := c.sendBundle(c.Auth.AuthorizedContext(ctx), &proto.Bundle{Packets: packets, Header: nil}, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	err := c.JitoRpcConn.RPCCallForInto(ctx, out, "simulateBundle", []interface{}{bundleParams, simulationConfigs})
-	return out, err
+	return c.ConfirmBundle(ctx, resp.Uuid, bundleSignatures, config)
 }
 
-func (c *Client) AssembleBundle(transactions []types.Transaction) (*proto.Bundle, error) {
+func (c *Client) handleBundleResult(bundleResult *proto.BundleResult) error {
+	return NewBundleOutcome(bundleResult).Err
+}
+
+func (c *Client) AssembleBundle(transactions []pkg.Transaction) (*proto.Bundle, error) {
 	packets, err := assemblePackets(transactions)
 	if err != nil {
 		return nil, err
@@ -423,17 +347,32 @@ func (c *Client) GenerateTipInstruction(tipAmount uint64, from, tipAccount solan
 }
 
 // GenerateTipRandomAccountInstruction functions similarly to GenerateTipInstruction, but it selects a random tip account.
+// The tip account comes from TipAccounts when set, from GetRandomTipAccount otherwise.
 func (c *Client) GenerateTipRandomAccountInstruction(tipAmount uint64, from solana.PublicKey) (solana.Instruction, error) {
+	if c.TipAccounts != nil {
+		tipAccount, err := c.TipAccounts.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		return c.GenerateTipInstruction(tipAmount, from, tipAccount), nil
+	}
+
 	tipAccount, err := c.GetRandomTipAccount()
 	if err != nil {
 		return nil, err
 	}
 
-	return system.NewTransferInstruction(tipAmount, from, solana.MustPublicKeyFromBase58(tipAccount)).Build(), nil
+	key, err := solana.PublicKeyFromBase58(tipAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GenerateTipInstruction(tipAmount, from, key), nil
 }
 
 // assemblePackets is a function that converts a slice of transactions to a slice of protobuf packets.
-func assemblePackets(transactions []types.Transaction) ([]*proto.Packet, error) {
+func assemblePackets(transactions []pkg.Transaction) ([]*proto.Packet, error) {
 	packets := make([]*proto.Packet, 0, len(transactions))
 
 	for i, tx := range transactions {
@@ -447,41 +386,3 @@ func assemblePackets(transactions []types.Transaction) ([]*proto.Packet, error)
 
 	return packets, nil
 }
-
-type BundleRejectionError struct {
-	Message string
-}
-
-func (e BundleRejectionError) Error() string {
-	return e.Message
-}
-
-func NewStateAuctionBidRejectedError(auction string, tip uint64) error {
-	return BundleRejectionError{
-		Message: fmt.Sprintf("bundle lost state auction, auction: %s, tip %d lamports", auction, tip),
-	}
-}
-
-func NewWinningBatchBidRejectedError(auction string, tip uint64) error {
-	return BundleRejectionError{
-		Message: fmt.Sprintf("bundle won state auction but failed global auction, auction %s, tip %d lamports", auction, tip),
-	}
-}
-
-func NewSimulationFailureError(tx string, message string) error {
-	return BundleRejectionError{
-		Message: fmt.Sprintf("bundle simulation failure on tx %s, message: %s", tx, message),
-	}
-}
-
-func NewInternalError(message string) error {
-	return BundleRejectionError{
-		Message: fmt.Sprintf("internal error %s", message),
-	}
-}
-
-func NewDroppedBundle(message string) error {
-	return BundleRejectionError{
-		Message: fmt.Sprintf("bundle dropped %s", message),
-	}
-}
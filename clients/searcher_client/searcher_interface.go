@@ -0,0 +1,91 @@
+package searcher_client
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// Searcher is the block engine API independent of its transport. It is implemented over gRPC by Client.Searcher,
+// over the JSON-RPC bundles API by jsonrpc_client.Client, and in memory by searchertest.Searcher.
+type Searcher interface {
+	// SendBundle sends an assembled bundle, the Uuid of the response identifies it.
+	SendBundle(ctx context.Context, bundle *proto.Bundle) (*proto.SendBundleResponse, error)
+	// GetTipAccounts returns the tip accounts of the block engine.
+	GetTipAccounts(ctx context.Context) ([]solana.PublicKey, error)
+	// WatchBundle streams the outcomes of a bundle in order. The channel is closed after the final outcome or once ctx is done.
+	WatchBundle(ctx context.Context, bundleId string) <-chan *BundleOutcome
+	// GetNextScheduledLeader returns the next leader connected to the block engine, restricted to regions when not empty.
+	GetNextScheduledLeader(ctx context.Context, regions []string) (*proto.NextScheduledLeaderResponse, error)
+}
+
+// Searcher returns the Searcher of the client, its calls are bound to their context.
+func (c *Client) Searcher() Searcher {
+	return grpcSearcher{client: c}
+}
+
+// grpcSearcher adapts Client, whose methods predate Searcher, to it.
+type grpcSearcher struct {
+	client *Client
+}
+
+func (s grpcSearcher) SendBundle(ctx context.Context, bundle *proto.Bundle) (*proto.SendBundleResponse, error) {
+	return s.client.sendBundle(s.client.Auth.AuthorizedContext(ctx), bundle)
+}
+
+func (s grpcSearcher) GetTipAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	resp, err := s.client.SearcherService.GetTipAccounts(s.client.Auth.AuthorizedContext(ctx), &proto.GetTipAccountsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]solana.PublicKey, 0, len(resp.Accounts))
+	for _, account := range resp.Accounts {
+		key, err := solana.PublicKeyFromBase58(account)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, key)
+	}
+
+	return accounts, nil
+}
+
+func (s grpcSearcher) WatchBundle(ctx context.Context, bundleId string) <-chan *BundleOutcome {
+	return s.client.WatchBundle(ctx, bundleId)
+}
+
+func (s grpcSearcher) GetNextScheduledLeader(ctx context.Context, regions []string) (*proto.NextScheduledLeaderResponse, error) {
+	return s.client.SearcherService.GetNextScheduledLeader(s.client.Auth.AuthorizedContext(ctx), &proto.NextScheduledLeaderRequest{Regions: regions})
+}
+
+// WatchBundle streams the outcomes of bundleId received by Tracker, the bundle is untracked once the channel is closed.
+func (c *Client) WatchBundle(ctx context.Context, bundleId string) <-chan *BundleOutcome {
+	handle := c.Tracker.Track(bundleId)
+	outcomes := make(chan *BundleOutcome)
+
+	go func() {
+		defer close(outcomes)
+		defer c.Tracker.Untrack(bundleId)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case result, ok := <-handle.Results():
+				if !ok {
+					return
+				}
+
+				select {
+				case outcomes <- NewBundleOutcome(result):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return outcomes
+}
@@ -0,0 +1,58 @@
+package searcher_client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// sendAndWatch is a strategy written against Searcher, it sends bundle and returns every outcome until the final one.
+func sendAndWatch(ctx context.Context, searcher Searcher, bundle *proto.Bundle, publish func(id string)) ([]*BundleOutcome, error) {
+	resp, err := searcher.SendBundle(ctx, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := searcher.WatchBundle(ctx, resp.Uuid)
+	publish(resp.Uuid)
+
+	var out []*BundleOutcome
+	for outcome := range outcomes {
+		out = append(out, outcome)
+	}
+	return out, ctx.Err()
+}
+
+func Test_Searcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	states := func(outcomes []*BundleOutcome) []BundleState {
+		var out []BundleState
+		for _, outcome := range outcomes {
+			out = append(out, outcome.State)
+		}
+		return out
+	}
+
+	t.Run("Client", func(t *testing.T) {
+		client, service, stream := newRegionClient(ctx)
+		service.setNextLeader(1, 2, "ny")
+
+		outcomes, err := sendAndWatch(ctx, client.Searcher(), &proto.Bundle{}, func(id string) {
+			stream.results <- acceptedResult(id, 2)
+			stream.results <- processedResult(id, 2)
+			stream.results <- finalizedResult(id)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []BundleState{BundleStateAccepted, BundleStateProcessed, BundleStateFinalized}, states(outcomes))
+		assert.Len(t, service.sentBundles(), 1)
+
+		leader, err := client.Searcher().GetNextScheduledLeader(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), leader.NextLeaderSlot)
+	})
+}
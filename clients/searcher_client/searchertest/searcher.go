@@ -0,0 +1,140 @@
+// Package searchertest provides an in-memory searcher_client.Searcher to test strategies without a block engine.
+package searchertest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/clients/searcher_client"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+var _ searcher_client.Searcher = (*Searcher)(nil)
+
+// Searcher is an in-memory searcher_client.Searcher.
+// Sent bundles are recorded, their outcomes are emitted with Publish.
+type Searcher struct {
+	// TipAccounts are returned by GetTipAccounts.
+	TipAccounts []solana.PublicKey
+	// NextLeader is returned by GetNextScheduledLeader.
+	NextLeader *proto.NextScheduledLeaderResponse
+	// SendErr, when set, is returned by SendBundle instead of accepting the bundle.
+	SendErr error
+
+	mu       sync.Mutex
+	sent     []*proto.Bundle
+	outcomes map[string][]*searcher_client.BundleOutcome
+	watchers map[string][]*bundleWatcher
+}
+
+type bundleWatcher struct {
+	ctx      context.Context
+	outcomes chan *searcher_client.BundleOutcome
+}
+
+// NewSearcher creates a Searcher returning the mainnet tip accounts.
+func NewSearcher() *Searcher {
+	return &Searcher{
+		TipAccounts: jito_go.MainnetTipAccounts,
+		NextLeader:  &proto.NextScheduledLeaderResponse{},
+		outcomes:    make(map[string][]*searcher_client.BundleOutcome),
+		watchers:    make(map[string][]*bundleWatcher),
+	}
+}
+
+// SendBundle records bundle, the bundles are identified as "bundle-1", "bundle-2"... in order.
+func (f *Searcher) SendBundle(ctx context.Context, bundle *proto.Bundle) (*proto.SendBundleResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.SendErr != nil {
+		return nil, f.SendErr
+	}
+
+	f.sent = append(f.sent, bundle)
+	return &proto.SendBundleResponse{Uuid: fmt.Sprintf("bundle-%d", len(f.sent))}, nil
+}
+
+// Sent returns the bundles sent so far, in order.
+func (f *Searcher) Sent() []*proto.Bundle {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.Bundle(nil), f.sent...)
+}
+
+func (f *Searcher) GetTipAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	if len(f.TipAccounts) == 0 {
+		return nil, searcher_client.ErrNoTipAccount
+	}
+	return f.TipAccounts, ctx.Err()
+}
+
+func (f *Searcher) GetNextScheduledLeader(ctx context.Context, regions []string) (*proto.NextScheduledLeaderResponse, error) {
+	return f.NextLeader, ctx.Err()
+}
+
+// WatchBundle streams the outcomes published for bundleId, the ones published before the call are replayed first.
+func (f *Searcher) WatchBundle(ctx context.Context, bundleId string) <-chan *searcher_client.BundleOutcome {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	past := f.outcomes[bundleId]
+	w := &bundleWatcher{ctx: ctx, outcomes: make(chan *searcher_client.BundleOutcome, len(past)+64)}
+	for _, outcome := range past {
+		w.outcomes <- outcome
+	}
+
+	if len(past) != 0 && past[len(past)-1].Final() {
+		close(w.outcomes)
+		return w.outcomes
+	}
+
+	f.watchers[bundleId] = append(f.watchers[bundleId], w)
+	context.AfterFunc(ctx, func() { f.unwatch(bundleId, w) })
+
+	return w.outcomes
+}
+
+// Publish emits outcome to the watchers of its bundle, which are closed after a final outcome.
+// It blocks while a watcher has 64 outcomes pending.
+func (f *Searcher) Publish(outcome *searcher_client.BundleOutcome) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.outcomes[outcome.BundleId] = append(f.outcomes[outcome.BundleId], outcome)
+
+	for _, w := range f.watchers[outcome.BundleId] {
+		select {
+		case w.outcomes <- outcome:
+		case <-w.ctx.Done():
+		}
+	}
+
+	if outcome.Final() {
+		for _, w := range f.watchers[outcome.BundleId] {
+			close(w.outcomes)
+		}
+		delete(f.watchers, outcome.BundleId)
+	}
+}
+
+func (f *Searcher) unwatch(bundleId string, w *bundleWatcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	watchers := f.watchers[bundleId]
+	for i, watcher := range watchers {
+		if watcher == w {
+			f.watchers[bundleId] = append(watchers[:i], watchers[i+1:]...)
+			close(w.outcomes)
+			return
+		}
+	}
+}
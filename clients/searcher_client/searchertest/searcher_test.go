@@ -0,0 +1,73 @@
+package searchertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/clients/searcher_client"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Searcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("SendAndWatch", func(t *testing.T) {
+		fake := NewSearcher()
+
+		var searcher searcher_client.Searcher = fake
+		resp, err := searcher.SendBundle(ctx, &proto.Bundle{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "bundle-1", resp.Uuid)
+
+		outcomes := searcher.WatchBundle(ctx, resp.Uuid)
+		fake.Publish(&searcher_client.BundleOutcome{BundleId: resp.Uuid, State: searcher_client.BundleStateAccepted, Slot: 2})
+		fake.Publish(searcher_client.NewBundleOutcome(&proto.BundleResult{BundleId: resp.Uuid, Result: &proto.BundleResult_Rejected{Rejected: &proto.Rejected{
+			Reason: &proto.Rejected_StateAuctionBidRejected{StateAuctionBidRejected: &proto.StateAuctionBidRejected{SimulatedBidLamports: 1000}},
+		}}}))
+
+		var states []searcher_client.BundleState
+		var last *searcher_client.BundleOutcome
+		for outcome := range outcomes {
+			states = append(states, outcome.State)
+			last = outcome
+		}
+		if assert.Equal(t, []searcher_client.BundleState{searcher_client.BundleStateAccepted, searcher_client.BundleStateRejected}, states) {
+			assert.ErrorIs(t, last.Err, searcher_client.ErrStateAuctionBidRejected)
+		}
+		assert.Len(t, fake.Sent(), 1)
+
+		replayed := fake.WatchBundle(ctx, "bundle-1")
+		assert.Equal(t, searcher_client.BundleStateAccepted, (<-replayed).State)
+		assert.Equal(t, searcher_client.BundleStateRejected, (<-replayed).State)
+		_, ok := <-replayed
+		assert.False(t, ok)
+
+		tips, err := fake.GetTipAccounts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, jito_go.MainnetTipAccounts, tips)
+
+		fake.SendErr = errors.New("unavailable")
+		_, err = fake.SendBundle(ctx, &proto.Bundle{})
+		assert.ErrorIs(t, err, fake.SendErr)
+	})
+
+	t.Run("WatchStopsOnContext", func(t *testing.T) {
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		outcomes := NewSearcher().WatchBundle(watchCtx, "bundle-1")
+		watchCancel()
+
+		select {
+		case _, ok := <-outcomes:
+			assert.False(t, ok)
+		case <-ctx.Done():
+			t.Fatal("watch not closed")
+		}
+	})
+}
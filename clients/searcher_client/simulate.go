@@ -0,0 +1,402 @@
+package searcher_client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go/pkg"
+)
+
+var ErrSimulationConfigLength = errors.New("pre/post execution account config length must match bundle length")
+
+type SimulateBundleConfig struct {
+	PreExecutionAccountsConfigs  []ExecutionAccounts `json:"preExecutionAccountsConfigs"`
+	PostExecutionAccountsConfigs []ExecutionAccounts `json:"postExecutionAccountsConfigs"`
+	// TransactionEncoding is the encoding of SimulateBundleParams.EncodedTransactions, base64 when empty.
+	TransactionEncoding string `json:"transactionEncoding,omitempty"`
+	// SimulationBank selects the bank the bundle is simulated on, the working bank when nil.
+	SimulationBank *SimulationBank `json:"simulationBank,omitempty"`
+	// SkipSigVerify simulates without verifying the transaction signatures.
+	SkipSigVerify bool `json:"skipSigVerify,omitempty"`
+	// ReplaceRecentBlockhash simulates with the latest blockhash in place of the transactions' one.
+	ReplaceRecentBlockhash bool `json:"replaceRecentBlockhash,omitempty"`
+}
+
+type ExecutionAccounts struct {
+	Encoding  string   `json:"encoding"`
+	Addresses []string `json:"addresses"`
+}
+
+// NewExecutionAccounts returns the base64 ExecutionAccounts config of addresses.
+func NewExecutionAccounts(addresses ...solana.PublicKey) ExecutionAccounts {
+	accounts := ExecutionAccounts{Encoding: "base64", Addresses: make([]string, 0, len(addresses))}
+	for _, address := range addresses {
+		accounts.Addresses = append(accounts.Addresses, address.String())
+	}
+	return accounts
+}
+
+// NewSimulateBundleConfig returns a config requesting the pre and post execution state of accounts for each of the count transactions.
+func NewSimulateBundleConfig(count int, accounts ...solana.PublicKey) SimulateBundleConfig {
+	config := SimulateBundleConfig{
+		PreExecutionAccountsConfigs:  make([]ExecutionAccounts, count),
+		PostExecutionAccountsConfigs: make([]ExecutionAccounts, count),
+	}
+	for i := 0; i < count; i++ {
+		config.PreExecutionAccountsConfigs[i] = NewExecutionAccounts(accounts...)
+		config.PostExecutionAccountsConfigs[i] = NewExecutionAccounts(accounts...)
+	}
+	return config
+}
+
+// SimulationBank selects the bank a bundle is simulated on. Set one of its fields, or none for the working bank.
+type SimulationBank struct {
+	Commitment rpc.CommitmentType
+	Slot       uint64
+}
+
+func (b SimulationBank) MarshalJSON() ([]byte, error) {
+	switch {
+	case b.Commitment != "":
+		return json.Marshal(map[string]interface{}{"commitment": map[string]rpc.CommitmentType{"commitment": b.Commitment}})
+	case b.Slot != 0:
+		return json.Marshal(map[string]uint64{"slot": b.Slot})
+	default:
+		return json.Marshal("tip")
+	}
+}
+
+type SimulateBundleParams struct {
+	EncodedTransactions []string `json:"encodedTransactions"`
+}
+
+// NewSimulateBundleParams base64 encodes transactions.
+func NewSimulateBundleParams(transactions []*solana.Transaction) (SimulateBundleParams, error) {
+	params := SimulateBundleParams{EncodedTransactions: make([]string, 0, len(transactions))}
+	for i, tx := range transactions {
+		encoded, err := tx.ToBase64()
+		if err != nil {
+			return SimulateBundleParams{}, fmt.Errorf("%d: %w", i, err)
+		}
+		params.EncodedTransactions = append(params.EncodedTransactions, encoded)
+	}
+	return params, nil
+}
+
+type SimulatedBundleResponse struct {
+	Context rpc.Context                   `json:"context"`
+	Value   SimulatedBundleResponseStruct `json:"value"`
+}
+
+type SimulatedBundleResponseStruct struct {
+	Summary           SimulationSummary   `json:"summary"`
+	TransactionResult []TransactionResult `json:"transactionResults"`
+}
+
+// SimulationSummary is either succeeded, or failed with Failed set.
+type SimulationSummary struct {
+	Failed *SimulationFailure
+}
+
+// Succeeded reports whether every transaction of the bundle succeeded.
+func (s SimulationSummary) Succeeded() bool {
+	return s.Failed == nil
+}
+
+func (s *SimulationSummary) UnmarshalJSON(data []byte) error {
+	var succeeded string
+	if err := json.Unmarshal(data, &succeeded); err == nil {
+		if succeeded != "succeeded" {
+			return fmt.Errorf("unknown simulation summary %q", succeeded)
+		}
+		s.Failed = nil
+		return nil
+	}
+
+	var failed struct {
+		Failed *SimulationFailure `json:"failed"`
+	}
+	if err := json.Unmarshal(data, &failed); err != nil {
+		return err
+	}
+	if failed.Failed == nil {
+		return fmt.Errorf("unknown simulation summary %s", data)
+	}
+
+	s.Failed = failed.Failed
+	return nil
+}
+
+func (s SimulationSummary) MarshalJSON() ([]byte, error) {
+	if s.Failed == nil {
+		return json.Marshal("succeeded")
+	}
+	return json.Marshal(map[string]*SimulationFailure{"failed": s.Failed})
+}
+
+// SimulationFailure describes why a bundle simulation failed.
+type SimulationFailure struct {
+	Error       BundleExecutionError `json:"error"`
+	TxSignature *string              `json:"tx_signature"`
+	// TxIndex is the index of the failing transaction in the bundle, NoTransactionIndex if unknown.
+	TxIndex int `json:"-"`
+}
+
+// BundleExecutionError is the reason of a bundle simulation failure, e.g. Kind "TransactionFailure".
+type BundleExecutionError struct {
+	Kind string
+	// Signature is set on TransactionFailure errors.
+	Signature string
+	Message   string
+	Raw       json.RawMessage
+}
+
+func (e *BundleExecutionError) Error() string {
+	switch {
+	case e.Signature != "":
+		return fmt.Sprintf("%s: transaction %s: %s", e.Kind, e.Signature, e.Message)
+	case e.Message != "":
+		return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+	default:
+		return e.Kind
+	}
+}
+
+func (e *BundleExecutionError) UnmarshalJSON(data []byte) error {
+	e.Raw = append(json.RawMessage(nil), data...)
+
+	if err := json.Unmarshal(data, &e.Kind); err == nil {
+		return nil
+	}
+
+	var variant map[string]json.RawMessage
+	if err := json.Unmarshal(data, &variant); err != nil {
+		return err
+	}
+
+	for kind, value := range variant {
+		e.Kind = kind
+
+		// TransactionFailure is [signature, error], other variants hold a single message
+		var tuple []string
+		if err := json.Unmarshal(value, &tuple); err == nil && len(tuple) == 2 {
+			e.Signature, e.Message = tuple[0], tuple[1]
+			continue
+		}
+		if err := json.Unmarshal(value, &e.Message); err != nil {
+			e.Message = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (e BundleExecutionError) MarshalJSON() ([]byte, error) {
+	if e.Raw != nil {
+		return e.Raw, nil
+	}
+	return json.Marshal(e.Kind)
+}
+
+type TransactionResult struct {
+	Err                   *TransactionError `json:"err,omitempty"`
+	Logs                  []string          `json:"logs,omitempty"`
+	PreExecutionAccounts  []Account         `json:"preExecutionAccounts,omitempty"`
+	PostExecutionAccounts []Account         `json:"postExecutionAccounts,omitempty"`
+	UnitsConsumed         *uint64           `json:"unitsConsumed,omitempty"`
+	ReturnData            *ReturnData       `json:"returnData,omitempty"`
+}
+
+// TransactionError is a Solana TransactionError, e.g. "AccountInUse" or {"InstructionError":[0,{"Custom":1}]}.
+type TransactionError struct {
+	Kind string
+	// InstructionIndex and InstructionError are set on InstructionError errors.
+	InstructionIndex *int
+	InstructionError string
+	// Custom is the program error code of a Custom InstructionError.
+	Custom *uint32
+	Raw    json.RawMessage
+}
+
+func (e *TransactionError) Error() string {
+	switch {
+	case e.Custom != nil:
+		return fmt.Sprintf("%s: instruction %d: custom program error: %#x", e.Kind, *e.InstructionIndex, *e.Custom)
+	case e.InstructionIndex != nil:
+		return fmt.Sprintf("%s: instruction %d: %s", e.Kind, *e.InstructionIndex, e.InstructionError)
+	case e.Kind != "":
+		return e.Kind
+	default:
+		return string(e.Raw)
+	}
+}
+
+func (e *TransactionError) UnmarshalJSON(data []byte) error {
+	e.Raw = append(json.RawMessage(nil), data...)
+
+	if err := json.Unmarshal(data, &e.Kind); err == nil {
+		return nil
+	}
+
+	var variant map[string]json.RawMessage
+	if err := json.Unmarshal(data, &variant); err != nil {
+		return err
+	}
+
+	for kind, value := range variant {
+		e.Kind = kind
+		if kind != "InstructionError" {
+			continue
+		}
+
+		var tuple []json.RawMessage
+		if err := json.Unmarshal(value, &tuple); err != nil || len(tuple) != 2 {
+			return fmt.Errorf("invalid InstructionError %s", value)
+		}
+
+		var index int
+		if err := json.Unmarshal(tuple[0], &index); err != nil {
+			return err
+		}
+		e.InstructionIndex = &index
+
+		if err := json.Unmarshal(tuple[1], &e.InstructionError); err == nil {
+			continue
+		}
+
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(tuple[1], &inner); err != nil {
+			return err
+		}
+		for name, detail := range inner {
+			e.InstructionError = name
+			if name == "Custom" {
+				var code uint32
+				if err := json.Unmarshal(detail, &code); err != nil {
+					return err
+				}
+				e.Custom = &code
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e TransactionError) MarshalJSON() ([]byte, error) {
+	if e.Raw != nil {
+		return e.Raw, nil
+	}
+	return json.Marshal(e.Kind)
+}
+
+type Account struct {
+	Executable bool                 `json:"executable"`
+	Owner      solana.PublicKey     `json:"owner"`
+	Lamports   uint64               `json:"lamports"`
+	Data       *rpc.DataBytesOrJSON `json:"data"`
+	RentEpoch  *big.Int             `json:"rentEpoch,omitempty"`
+}
+
+// DataBytes returns the decoded account data.
+func (a *Account) DataBytes() []byte {
+	if a.Data == nil {
+		return nil
+	}
+	return a.Data.GetBinary()
+}
+
+type ReturnData struct {
+	ProgramId string    `json:"programId"`
+	Data      [2]string `json:"data"`
+}
+
+// Bytes returns the decoded return data, only the base64 encoding is returned by simulateBundle.
+func (r *ReturnData) Bytes() ([]byte, error) {
+	if r.Data[1] != "" && r.Data[1] != "base64" {
+		return nil, fmt.Errorf("unsupported return data encoding %q", r.Data[1])
+	}
+	return base64.StdEncoding.DecodeString(r.Data[0])
+}
+
+// SimulateBundle is an RPC method that simulates a Jito bundle – exclusively available to Jito-Solana validator.
+// Empty pre/post execution account configs are filled to match the bundle length.
+func (c *Client) SimulateBundle(ctx context.Context, bundleParams SimulateBundleParams, simulationConfigs SimulateBundleConfig) (*SimulatedBundleResponse, error) {
+	count := len(bundleParams.EncodedTransactions)
+	simulationConfigs.PreExecutionAccountsConfigs = fillExecutionAccounts(simulationConfigs.PreExecutionAccountsConfigs, count)
+	simulationConfigs.PostExecutionAccountsConfigs = fillExecutionAccounts(simulationConfigs.PostExecutionAccountsConfigs, count)
+
+	if len(simulationConfigs.PreExecutionAccountsConfigs) != count || len(simulationConfigs.PostExecutionAccountsConfigs) != count {
+		return nil, ErrSimulationConfigLength
+	}
+
+	out := new(SimulatedBundleResponse)
+	if err := c.JitoRpcConn.RPCCallForInto(ctx, out, "simulateBundle", []interface{}{bundleParams, simulationConfigs}); err != nil {
+		return nil, err
+	}
+
+	if failed := out.Value.Summary.Failed; failed != nil {
+		failed.TxIndex = failingTransactionIndex(bundleParams, simulationConfigs.TransactionEncoding, out)
+	}
+
+	return out, nil
+}
+
+// SimulateTransactions simulates transactions as a bundle, see SimulateBundle.
+func (c *Client) SimulateTransactions(ctx context.Context, transactions []*solana.Transaction, simulationConfigs SimulateBundleConfig) (*SimulatedBundleResponse, error) {
+	params, err := NewSimulateBundleParams(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	simulationConfigs.TransactionEncoding = "base64"
+	return c.SimulateBundle(ctx, params, simulationConfigs)
+}
+
+func fillExecutionAccounts(configs []ExecutionAccounts, count int) []ExecutionAccounts {
+	if len(configs) != 0 {
+		return configs
+	}
+
+	configs = make([]ExecutionAccounts, count)
+	for i := range configs {
+		configs[i] = NewExecutionAccounts()
+	}
+	return configs
+}
+
+// failingTransactionIndex finds the failing transaction by signature, or by its error in the transaction results.
+func failingTransactionIndex(params SimulateBundleParams, encoding string, resp *SimulatedBundleResponse) int {
+	failed := resp.Value.Summary.Failed
+
+	signature := failed.Error.Signature
+	if signature == "" && failed.TxSignature != nil {
+		signature = *failed.TxSignature
+	}
+
+	if signature != "" && (encoding == "" || encoding == "base64") {
+		for i, encoded := range params.EncodedTransactions {
+			data, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				continue
+			}
+			if sig, err := pkg.ExtractSigFromSerializedTx(data); err == nil && sig.String() == signature {
+				return i
+			}
+		}
+	}
+
+	for i, result := range resp.Value.TransactionResult {
+		if result.Err != nil {
+			return i
+		}
+	}
+
+	return NoTransactionIndex
+}
@@ -0,0 +1,190 @@
+package searcher_client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSimulateBundleServer answers simulateBundle with result, and sends the received params on the returned channel.
+func newSimulateBundleServer(t *testing.T, result func(txns int) interface{}) (*httptest.Server, chan []json.RawMessage) {
+	params := make(chan []json.RawMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Params []json.RawMessage
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+
+		var bundle SimulateBundleParams
+		if err := json.Unmarshal(req.Params[0], &bundle); err != nil {
+			t.Error(err)
+			return
+		}
+		params <- req.Params
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  result(len(bundle.EncodedTransactions)),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, params
+}
+
+func Test_SimulateBundle(t *testing.T) {
+	ctx := context.Background()
+	payer := solana.NewWallet().PrivateKey
+
+	txns := make([]*solana.Transaction, 2)
+	for i := range txns {
+		tx, err := solana.NewTransaction(
+			[]solana.Instruction{system.NewTransferInstruction(uint64(i+1), payer.PublicKey(), solana.NewWallet().PublicKey()).Build()},
+			solana.Hash{1},
+			solana.TransactionPayer(payer.PublicKey()),
+		)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		if _, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer }); !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		txns[i] = tx
+	}
+
+	t.Run("Failed", func(t *testing.T) {
+		server, params := newSimulateBundleServer(t, func(int) interface{} {
+			return map[string]interface{}{
+				"context": map[string]interface{}{"slot": 7},
+				"value": map[string]interface{}{
+					"summary": map[string]interface{}{
+						"failed": map[string]interface{}{
+							"error":        map[string]interface{}{"TransactionFailure": []string{txns[1].Signatures[0].String(), "custom program error: 0x1"}},
+							"tx_signature": txns[1].Signatures[0].String(),
+						},
+					},
+					"transactionResults": []interface{}{
+						map[string]interface{}{
+							"err":           nil,
+							"logs":          []string{"Program 11111111111111111111111111111111 success"},
+							"unitsConsumed": 150,
+							"preExecutionAccounts": []interface{}{map[string]interface{}{
+								"executable": false,
+								"owner":      solana.SystemProgramID.String(),
+								"lamports":   10,
+								"data":       []string{base64.StdEncoding.EncodeToString([]byte{1, 2}), "base64"},
+								"rentEpoch":  json.Number("18446744073709551615"),
+							}},
+							"returnData": map[string]interface{}{
+								"programId": solana.SystemProgramID.String(),
+								"data":      []string{base64.StdEncoding.EncodeToString([]byte{3}), "base64"},
+							},
+						},
+						map[string]interface{}{
+							"err":           map[string]interface{}{"InstructionError": []interface{}{0, map[string]interface{}{"Custom": 1}}},
+							"unitsConsumed": 300,
+						},
+					},
+				},
+			}
+		})
+
+		client := &Client{JitoRpcConn: rpc.New(server.URL)}
+		config := NewSimulateBundleConfig(len(txns), payer.PublicKey())
+		config.SkipSigVerify = true
+		config.ReplaceRecentBlockhash = true
+		config.SimulationBank = &SimulationBank{Commitment: rpc.CommitmentConfirmed}
+
+		resp, err := client.SimulateTransactions(ctx, txns, config)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		sent := <-params
+		var options map[string]interface{}
+		assert.NoError(t, json.Unmarshal(sent[1], &options))
+		assert.Equal(t, true, options["skipSigVerify"])
+		assert.Equal(t, true, options["replaceRecentBlockhash"])
+		assert.Equal(t, "base64", options["transactionEncoding"])
+		assert.Equal(t, map[string]interface{}{"commitment": map[string]interface{}{"commitment": "confirmed"}}, options["simulationBank"])
+		assert.Len(t, options["postExecutionAccountsConfigs"], 2)
+
+		assert.Equal(t, uint64(7), resp.Context.Slot)
+		summary := resp.Value.Summary
+		assert.False(t, summary.Succeeded())
+		if assert.NotNil(t, summary.Failed) {
+			assert.Equal(t, 1, summary.Failed.TxIndex)
+			assert.Equal(t, "TransactionFailure", summary.Failed.Error.Kind)
+			assert.Equal(t, txns[1].Signatures[0].String(), summary.Failed.Error.Signature)
+		}
+
+		results := resp.Value.TransactionResult
+		if !assert.Len(t, results, 2) {
+			t.FailNow()
+		}
+		assert.Nil(t, results[0].Err)
+		assert.Equal(t, uint64(150), *results[0].UnitsConsumed)
+		assert.Equal(t, solana.SystemProgramID, results[0].PreExecutionAccounts[0].Owner)
+		assert.Equal(t, []byte{1, 2}, results[0].PreExecutionAccounts[0].DataBytes())
+		returned, err := results[0].ReturnData.Bytes()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{3}, returned)
+
+		txErr := results[1].Err
+		if assert.NotNil(t, txErr) {
+			assert.Equal(t, "InstructionError", txErr.Kind)
+			assert.Equal(t, 0, *txErr.InstructionIndex)
+			assert.Equal(t, "Custom", txErr.InstructionError)
+			assert.Equal(t, uint32(1), *txErr.Custom)
+			assert.EqualError(t, txErr, "InstructionError: instruction 0: custom program error: 0x1")
+		}
+	})
+
+	t.Run("Succeeded", func(t *testing.T) {
+		server, params := newSimulateBundleServer(t, func(txns int) interface{} {
+			return map[string]interface{}{
+				"context": map[string]interface{}{"slot": 1},
+				"value": map[string]interface{}{
+					"summary":            "succeeded",
+					"transactionResults": make([]interface{}, txns),
+				},
+			}
+		})
+
+		client := &Client{JitoRpcConn: rpc.New(server.URL)}
+		resp, err := client.SimulateTransactions(ctx, txns, SimulateBundleConfig{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.True(t, resp.Value.Summary.Succeeded())
+
+		var config SimulateBundleConfig
+		assert.NoError(t, json.Unmarshal((<-params)[1], &config))
+		assert.Len(t, config.PreExecutionAccountsConfigs, 2)
+		assert.Len(t, config.PostExecutionAccountsConfigs, 2)
+	})
+
+	t.Run("ConfigLength", func(t *testing.T) {
+		client := &Client{}
+		for _, config := range []SimulateBundleConfig{
+			{PreExecutionAccountsConfigs: []ExecutionAccounts{NewExecutionAccounts()}},
+			{PostExecutionAccountsConfigs: []ExecutionAccounts{NewExecutionAccounts()}},
+		} {
+			_, err := client.SimulateTransactions(ctx, txns, config)
+			assert.ErrorIs(t, err, ErrSimulationConfigLength)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+var ErrNoTipAccount = errors.New("no tip account available")
+
+// Network selects the compiled tip accounts used when the block engine is unreachable.
+type Network string
+
+const (
+	Mainnet Network = "mainnet"
+	Testnet Network = "testnet"
+)
+
+// TipAccounts returns the compiled tip accounts of the network.
+func (n Network) TipAccounts() []solana.PublicKey {
+	if n == Testnet {
+		return jito_go.TestnetTipAccounts
+	}
+	return jito_go.MainnetTipAccounts
+}
+
+// NetworkFromURL guesses the network of a block engine URL, e.g. jito_go.TestnetDallas.BlockEngineURL is Testnet.
+func NetworkFromURL(url string) Network {
+	if strings.Contains(url, "testnet") {
+		return Testnet
+	}
+	return Mainnet
+}
+
+// TipAccountStrategy picks the tip account of a bundle. Implementations must be safe for concurrent use.
+type TipAccountStrategy interface {
+	Select(accounts []solana.PublicKey) solana.PublicKey
+}
+
+// RandomStrategy picks a random tip account.
+type RandomStrategy struct{}
+
+func (RandomStrategy) Select(accounts []solana.PublicKey) solana.PublicKey {
+	return accounts[rand.Intn(len(accounts))]
+}
+
+// RoundRobinStrategy cycles thru the tip accounts.
+type RoundRobinStrategy struct {
+	next atomic.Uint64
+}
+
+func (s *RoundRobinStrategy) Select(accounts []solana.PublicKey) solana.PublicKey {
+	return accounts[(s.next.Add(1)-1)%uint64(len(accounts))]
+}
+
+// LeastRecentlyUsedStrategy picks the tip account that was not picked for the longest time,
+// spreading write locks across tip accounts.
+type LeastRecentlyUsedStrategy struct {
+	mu       sync.Mutex
+	lastUsed map[solana.PublicKey]uint64
+	clock    uint64
+}
+
+func (s *LeastRecentlyUsedStrategy) Select(accounts []solana.PublicKey) solana.PublicKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastUsed == nil {
+		s.lastUsed = make(map[solana.PublicKey]uint64)
+	}
+
+	selected := accounts[0]
+	for _, account := range accounts[1:] {
+		if s.lastUsed[account] < s.lastUsed[selected] {
+			selected = account
+		}
+	}
+
+	s.clock++
+	s.lastUsed[selected] = s.clock
+
+	return selected
+}
+
+// TipAccountProviderConfig configures a TipAccountProvider, zero values fall back to sensible defaults.
+type TipAccountProviderConfig struct {
+	// Network selects the fallback tip accounts, defaults to Mainnet.
+	Network Network
+	// TTL is how long fetched tip accounts are used before being refreshed, defaults to 10 minutes.
+	TTL time.Duration
+	// RefreshTimeout bounds each background refresh, defaults to 5s.
+	RefreshTimeout time.Duration
+	// Strategy defaults to RandomStrategy.
+	Strategy TipAccountStrategy
+}
+
+// TipAccountsFetcher fetches the tip accounts from the block engine.
+type TipAccountsFetcher func(ctx context.Context) ([]string, error)
+
+// TipAccountProvider caches the block engine tip accounts and refreshes them in the background.
+// It falls back to the compiled tip accounts of the configured network when they cannot be fetched.
+type TipAccountProvider struct {
+	fetch    TipAccountsFetcher
+	fallback []solana.PublicKey
+	ttl      time.Duration
+	timeout  time.Duration
+	strategy TipAccountStrategy
+
+	mu        sync.RWMutex
+	accounts  []solana.PublicKey
+	fetchedAt time.Time
+	err       error
+}
+
+// NewTipAccountProvider creates a TipAccountProvider refreshing its cache until ctx is done.
+func NewTipAccountProvider(ctx context.Context, fetch TipAccountsFetcher, config TipAccountProviderConfig) *TipAccountProvider {
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Minute
+	}
+	if config.RefreshTimeout <= 0 {
+		config.RefreshTimeout = 5 * time.Second
+	}
+	if config.Strategy == nil {
+		config.Strategy = RandomStrategy{}
+	}
+
+	p := &TipAccountProvider{
+		fetch:    fetch,
+		fallback: config.Network.TipAccounts(),
+		ttl:      config.TTL,
+		timeout:  config.RefreshTimeout,
+		strategy: config.Strategy,
+	}
+
+	go p.refreshLoop(ctx)
+
+	return p
+}
+
+// NewTipAccountProvider creates a TipAccountProvider fetching tip accounts thru GetTipAccounts.
+func (c *Client) NewTipAccountProvider(ctx context.Context, config TipAccountProviderConfig) *TipAccountProvider {
+	return NewTipAccountProvider(ctx, func(ctx context.Context) ([]string, error) {
+		resp, err := c.SearcherService.GetTipAccounts(c.Auth.AuthorizedContext(ctx), &proto.GetTipAccountsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Accounts, nil
+	}, config)
+}
+
+// Refresh fetches the tip accounts now.
+func (p *TipAccountProvider) Refresh(ctx context.Context) error {
+	raw, err := p.fetch(ctx)
+	if err == nil && len(raw) == 0 {
+		err = ErrNoTipAccount
+	}
+
+	var accounts []solana.PublicKey
+	if err == nil {
+		accounts = make([]solana.PublicKey, 0, len(raw))
+		for _, account := range raw {
+			var key solana.PublicKey
+			key, err = solana.PublicKeyFromBase58(account)
+			if err != nil {
+				break
+			}
+			accounts = append(accounts, key)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+	if err == nil {
+		p.accounts = accounts
+		p.fetchedAt = time.Now()
+	}
+
+	return err
+}
+
+// Accounts returns the cached tip accounts, or the compiled ones of the network if they are missing or expired.
+func (p *TipAccountProvider) Accounts() []solana.PublicKey {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.accounts) == 0 || time.Since(p.fetchedAt) > p.ttl {
+		return p.fallback
+	}
+
+	return p.accounts
+}
+
+// Err returns the error of the last refresh, nil if it succeeded.
+func (p *TipAccountProvider) Err() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.err
+}
+
+// Next selects a tip account with the configured strategy. It fits BundleBuilder.WithTipAccountFunc.
+func (p *TipAccountProvider) Next() (solana.PublicKey, error) {
+	accounts := p.Accounts()
+	if len(accounts) == 0 {
+		return solana.PublicKey{}, ErrNoTipAccount
+	}
+
+	return p.strategy.Select(accounts), nil
+}
+
+func (p *TipAccountProvider) refreshLoop(ctx context.Context) {
+	// retry sooner than the TTL while the block engine is unreachable
+	retry := min(p.ttl, 10*time.Second)
+
+	for {
+		delay := p.ttl / 2
+
+		refreshCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		if err := p.Refresh(refreshCtx); err != nil {
+			delay = retry
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
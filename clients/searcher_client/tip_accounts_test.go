@@ -0,0 +1,140 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TipAccountProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetched := []string{
+		jito_go.MainnetTipAccounts[0].String(),
+		jito_go.MainnetTipAccounts[1].String(),
+		jito_go.MainnetTipAccounts[2].String(),
+	}
+
+	// fetcher returns the accounts and error currently set, counting calls.
+	type fetcher struct {
+		mu       sync.Mutex
+		accounts []string
+		err      error
+		calls    int
+	}
+	newFetcher := func(accounts []string, err error) *fetcher {
+		return &fetcher{accounts: accounts, err: err}
+	}
+	fetch := func(f *fetcher) TipAccountsFetcher {
+		return func(context.Context) ([]string, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.calls++
+			return f.accounts, f.err
+		}
+	}
+
+	t.Run("FallbackOnError", func(t *testing.T) {
+		f := newFetcher(nil, errors.New("unavailable"))
+		p := NewTipAccountProvider(ctx, fetch(f), TipAccountProviderConfig{Network: Testnet, TTL: time.Hour})
+
+		assert.Error(t, p.Refresh(ctx))
+		assert.Equal(t, jito_go.TestnetTipAccounts, p.Accounts())
+
+		f.mu.Lock()
+		f.err = nil
+		f.accounts = nil
+		f.mu.Unlock()
+		assert.ErrorIs(t, p.Refresh(ctx), ErrNoTipAccount)
+		assert.Equal(t, jito_go.TestnetTipAccounts, p.Accounts())
+	})
+
+	t.Run("CachedUntilExpired", func(t *testing.T) {
+		f := newFetcher(fetched, nil)
+		p := NewTipAccountProvider(ctx, fetch(f), TipAccountProviderConfig{TTL: time.Hour})
+
+		assert.NoError(t, p.Refresh(ctx))
+		assert.NoError(t, p.Err())
+		assert.Equal(t, jito_go.MainnetTipAccounts[:3], p.Accounts())
+
+		for i := 0; i < 10; i++ {
+			account, err := p.Next()
+			assert.NoError(t, err)
+			assert.Contains(t, jito_go.MainnetTipAccounts[:3], account)
+		}
+
+		p.mu.Lock()
+		p.fetchedAt = time.Now().Add(-2 * time.Hour)
+		p.mu.Unlock()
+		assert.Equal(t, jito_go.MainnetTipAccounts, p.Accounts())
+	})
+
+	t.Run("BackgroundRefresh", func(t *testing.T) {
+		f := newFetcher(fetched, nil)
+		p := NewTipAccountProvider(ctx, fetch(f), TipAccountProviderConfig{TTL: 20 * time.Millisecond})
+
+		assert.Eventually(t, func() bool {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return f.calls >= 3
+		}, time.Second, 5*time.Millisecond)
+		assert.Len(t, p.Accounts(), 3)
+	})
+
+	t.Run("RefreshTimeout", func(t *testing.T) {
+		var mu sync.Mutex
+		calls := 0
+		// a hanging block engine, only the deadline of the refresh releases the call
+		hanging := func(ctx context.Context) ([]string, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		p := NewTipAccountProvider(ctx, hanging, TipAccountProviderConfig{TTL: 20 * time.Millisecond, RefreshTimeout: 10 * time.Millisecond})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return calls >= 2
+		}, time.Second, 5*time.Millisecond)
+		assert.ErrorIs(t, p.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("RoundRobin", func(t *testing.T) {
+		s := &RoundRobinStrategy{}
+		accounts := jito_go.MainnetTipAccounts[:3]
+		for i := 0; i < 6; i++ {
+			assert.Equal(t, accounts[i%3], s.Select(accounts))
+		}
+	})
+
+	t.Run("LeastRecentlyUsed", func(t *testing.T) {
+		s := &LeastRecentlyUsedStrategy{}
+		accounts := jito_go.MainnetTipAccounts[:4]
+
+		seen := make(map[solana.PublicKey]struct{})
+		for i := 0; i < len(accounts); i++ {
+			seen[s.Select(accounts)] = struct{}{}
+		}
+		assert.Len(t, seen, len(accounts))
+		assert.Equal(t, accounts[0], s.Select(accounts))
+	})
+
+	t.Run("NoAccount", func(t *testing.T) {
+		p := NewTipAccountProvider(ctx, fetch(newFetcher(nil, errors.New("unavailable"))), TipAccountProviderConfig{Network: "devnet"})
+		p.fallback = nil
+
+		_, err := p.Next()
+		assert.ErrorIs(t, err, ErrNoTipAccount)
+	})
+}
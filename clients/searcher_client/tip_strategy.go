@@ -0,0 +1,183 @@
+package searcher_client
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/pkg"
+)
+
+// MinTipLamports is the minimum tip accepted by the block engine.
+const MinTipLamports = 1000
+
+// TipStrategyConfig configures a TipStrategy, zero values fall back to sensible defaults.
+type TipStrategyConfig struct {
+	// TargetLandingProbability is the share of recently landed bundles the tip should beat, between 0.25 and 0.99.
+	// 0.75 tips the 75th landed tips percentile. Defaults to 0.5.
+	TargetLandingProbability float64
+	// MinLamports defaults to MinTipLamports.
+	MinLamports uint64
+	// MaxLamports caps the tip, 0 disables the cap.
+	MaxLamports uint64
+	// FallbackLamports is tipped while no tip info is available, defaults to MinLamports.
+	FallbackLamports uint64
+	// BidIncrement is how much a lost auction bid is raised by, defaults to 0.1 i.e. 10%.
+	BidIncrement float64
+	// FeedbackWindow is how long a lost auction raises the tip, defaults to 1 minute.
+	FeedbackWindow time.Duration
+}
+
+func (c TipStrategyConfig) withDefaults() TipStrategyConfig {
+	if c.TargetLandingProbability <= 0 {
+		c.TargetLandingProbability = 0.5
+	}
+	if c.MinLamports == 0 {
+		c.MinLamports = MinTipLamports
+	}
+	if c.MaxLamports != 0 && c.MaxLamports < c.MinLamports {
+		c.MaxLamports = c.MinLamports
+	}
+	if c.FallbackLamports == 0 {
+		c.FallbackLamports = c.MinLamports
+	}
+	if c.BidIncrement <= 0 {
+		c.BidIncrement = 0.1
+	}
+	if c.FeedbackWindow <= 0 {
+		c.FeedbackWindow = time.Minute
+	}
+	return c
+}
+
+// lostBid is a bid of ours which lost an auction.
+type lostBid struct {
+	lamports uint64
+	at       time.Time
+}
+
+// TipStrategy picks the tip of each bundle from the landed tips percentiles of the tip stream,
+// raised above the bids recently lost in auctions.
+type TipStrategy struct {
+	config TipStrategyConfig
+	info   func() *pkg.TipStreamInfo
+	now    func() time.Time
+
+	mu   sync.Mutex
+	lost []lostBid
+}
+
+// NewTipStrategy creates a TipStrategy reading tip infos from info, e.g. (*pkg.TipStream).Last.
+func NewTipStrategy(info func() *pkg.TipStreamInfo, config TipStrategyConfig) *TipStrategy {
+	return &TipStrategy{
+		config: config.withDefaults(),
+		info:   info,
+		now:    time.Now,
+	}
+}
+
+// Tip returns the lamports to tip, within the configured caps.
+func (s *TipStrategy) Tip() uint64 {
+	tip := s.config.FallbackLamports
+	if s.info != nil {
+		if info := s.info(); info != nil {
+			tip = percentileTip(info, s.config.TargetLandingProbability)
+		}
+	}
+
+	tip = max(tip, s.auctionFloor(), s.config.MinLamports)
+	if s.config.MaxLamports != 0 {
+		tip = min(tip, s.config.MaxLamports)
+	}
+
+	return tip
+}
+
+// Observe feeds the strategy with the outcome of a bundle, lost auctions raise the next tips.
+func (s *TipStrategy) Observe(outcome *BundleOutcome) {
+	if outcome != nil {
+		s.ObserveErr(outcome.Err)
+	}
+}
+
+// ObserveErr is like Observe for the error of a bundle, e.g. returned by BundleHandle.WaitOutcome.
+func (s *TipStrategy) ObserveErr(err error) {
+	var (
+		stateErr *StateAuctionBidRejectedError
+		batchErr *WinningBatchBidRejectedError
+		lamports uint64
+	)
+
+	switch {
+	case errors.As(err, &stateErr):
+		lamports = stateErr.SimulatedBidLamports
+	case errors.As(err, &batchErr):
+		lamports = batchErr.SimulatedBidLamports
+	default:
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lost = append(s.lost, lostBid{lamports: lamports, at: s.now()})
+}
+
+// auctionFloor returns the tip outbidding the bids lost within the feedback window, 0 if there is none.
+func (s *TipStrategy) auctionFloor() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.now().Add(-s.config.FeedbackWindow)
+	kept := s.lost[:0]
+
+	var highest uint64
+	for _, bid := range s.lost {
+		if bid.at.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, bid)
+		highest = max(highest, bid.lamports)
+	}
+	s.lost = kept
+
+	if highest == 0 {
+		return 0
+	}
+
+	return highest + max(1, uint64(math.Round(float64(highest)*s.config.BidIncrement)))
+}
+
+// percentileTip interpolates the landed tips percentiles at probability, in lamports.
+// The median is the mean of the instantaneous and EMA medians, damping spikes.
+func percentileTip(info *pkg.TipStreamInfo, probability float64) uint64 {
+	points := []struct{ p, sol float64 }{
+		{0.25, info.LandedTips25ThPercentile},
+		{0.50, (info.LandedTips50ThPercentile + info.EmaLandedTips50ThPercentile) / 2},
+		{0.75, info.LandedTips75ThPercentile},
+		{0.95, info.LandedTips95ThPercentile},
+		{0.99, info.LandedTips99ThPercentile},
+	}
+
+	// percentiles must not decrease, the smoothed median may exceed the 75th percentile
+	for i := 1; i < len(points); i++ {
+		points[i].sol = max(points[i].sol, points[i-1].sol)
+	}
+
+	sol := points[len(points)-1].sol
+	switch {
+	case probability <= points[0].p:
+		sol = points[0].sol
+	case probability < points[len(points)-1].p:
+		for i := 1; i < len(points); i++ {
+			if probability <= points[i].p {
+				lo, hi := points[i-1], points[i]
+				sol = lo.sol + (hi.sol-lo.sol)*(probability-lo.p)/(hi.p-lo.p)
+				break
+			}
+		}
+	}
+
+	return uint64(math.Round(sol * float64(solana.LAMPORTS_PER_SOL)))
+}
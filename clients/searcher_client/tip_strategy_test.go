@@ -0,0 +1,86 @@
+package searcher_client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TipStrategy(t *testing.T) {
+	info := &pkg.TipStreamInfo{
+		LandedTips25ThPercentile:    0.000010,
+		LandedTips50ThPercentile:    0.000020,
+		LandedTips75ThPercentile:    0.000100,
+		LandedTips95ThPercentile:    0.001,
+		LandedTips99ThPercentile:    0.01,
+		EmaLandedTips50ThPercentile: 0.000040,
+	}
+	last := func() *pkg.TipStreamInfo { return info }
+
+	t.Run("Percentiles", func(t *testing.T) {
+		for probability, expected := range map[float64]uint64{
+			0.1:   10_000,
+			0.25:  10_000,
+			0.5:   30_000,
+			0.625: 65_000,
+			0.75:  100_000,
+			0.99:  10_000_000,
+			1:     10_000_000,
+		} {
+			s := NewTipStrategy(last, TipStrategyConfig{TargetLandingProbability: probability})
+			assert.Equal(t, expected, s.Tip(), "probability %v", probability)
+		}
+	})
+
+	t.Run("Caps", func(t *testing.T) {
+		s := NewTipStrategy(last, TipStrategyConfig{TargetLandingProbability: 0.99, MaxLamports: 50_000})
+		assert.Equal(t, uint64(50_000), s.Tip())
+
+		s = NewTipStrategy(last, TipStrategyConfig{MinLamports: 100_000})
+		assert.Equal(t, uint64(100_000), s.Tip())
+
+		s = NewTipStrategy(func() *pkg.TipStreamInfo { return nil }, TipStrategyConfig{})
+		assert.Equal(t, uint64(MinTipLamports), s.Tip())
+	})
+
+	t.Run("AuctionFeedback", func(t *testing.T) {
+		now := time.Now()
+		s := NewTipStrategy(last, TipStrategyConfig{FeedbackWindow: time.Minute})
+		s.now = func() time.Time { return now }
+
+		s.Observe(&BundleOutcome{Err: &StateAuctionBidRejectedError{SimulatedBidLamports: 50_000}})
+		assert.Equal(t, uint64(55_000), s.Tip())
+
+		s.ObserveErr(&WinningBatchBidRejectedError{SimulatedBidLamports: 40_000})
+		s.ObserveErr(&SimulationFailureError{})
+		assert.Equal(t, uint64(55_000), s.Tip())
+
+		now = now.Add(2 * time.Minute)
+		assert.Equal(t, uint64(30_000), s.Tip())
+	})
+
+	t.Run("BundleBuilder", func(t *testing.T) {
+		payer := solana.NewWallet().PrivateKey
+		s := NewTipStrategy(last, TipStrategyConfig{})
+
+		txns, err := (&Client{}).NewBundleBuilder().
+			AddInstructions(solana.NewInstruction(solana.MemoProgramID, nil, []byte("tip"))).
+			WithSigners(payer).
+			WithTip(1).
+			WithTipStrategy(s).
+			WithTipAccount(jito_go.MainnetTipAccounts[0]).
+			WithBlockhash(solana.Hash{1}).
+			BuildTransactions(context.Background())
+		if !assert.NoError(t, err) || !assert.Len(t, txns, 1) {
+			t.FailNow()
+		}
+
+		tip := txns[0].Message.Instructions[1]
+		assert.Equal(t, uint64(30_000), uint64(tip.Data[4])|uint64(tip.Data[5])<<8|uint64(tip.Data[6])<<16)
+	})
+}
@@ -0,0 +1,338 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pvaronik/jito-go/proto"
+)
+
+var (
+	// ErrTrackerClosed is returned by a BundleHandle whose tracker stopped before the bundle reached a final state.
+	ErrTrackerClosed = errors.New("bundle tracker closed")
+	// ErrBundleUntracked is returned by a BundleHandle stopped before the bundle reached a final state.
+	ErrBundleUntracked = errors.New("bundle no longer tracked")
+)
+
+// orphanTTL is how long results of bundles that are not (yet) tracked are kept around.
+// SendBundle and the results stream race, so a result may arrive before Track is called.
+const orphanTTL = 30 * time.Second
+
+type orphanResults struct {
+	results  []*proto.BundleResult
+	received time.Time
+}
+
+// BundleTracker consumes a SubscribeBundleResults stream once and routes every proto.BundleResult
+// to the BundleHandle of the bundle it belongs to, using BundleResult.BundleId as key.
+type BundleTracker struct {
+	stream BundleResultsStream
+
+	mu      sync.Mutex
+	handles map[string]*BundleHandle
+	orphans map[string]*orphanResults
+	err     error
+	done    chan struct{}
+}
+
+// NewBundleTracker starts reading the provided stream until ctx is done or the stream fails.
+// The stream is either a SubscribeBundleResults stream or a BundleResultsHub Subscription.
+func NewBundleTracker(ctx context.Context, stream BundleResultsStream) *BundleTracker {
+	t := &BundleTracker{
+		stream:  stream,
+		handles: make(map[string]*BundleHandle),
+		orphans: make(map[string]*orphanResults),
+		done:    make(chan struct{}),
+	}
+
+	go t.run(ctx)
+
+	return t
+}
+
+// Track returns the handle of the bundle identified by uuid (SendBundleResponse.Uuid).
+// Results received before the call are replayed on the handle in order.
+func (t *BundleTracker) Track(uuid string) *BundleHandle {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if h, ok := t.handles[uuid]; ok {
+		return h
+	}
+
+	h := newBundleHandle(uuid)
+	if t.err != nil {
+		h.fail(t.err)
+		return h
+	}
+
+	h.tracker = t
+
+	t.handles[uuid] = h
+	if orphan, ok := t.orphans[uuid]; ok {
+		delete(t.orphans, uuid)
+		for _, result := range orphan.results {
+			t.dispatch(h, result)
+		}
+	}
+
+	return h
+}
+
+// Untrack stops routing results to the handle of uuid and releases it, see BundleHandle.Stop.
+func (t *BundleTracker) Untrack(uuid string) {
+	t.mu.Lock()
+	h, ok := t.handles[uuid]
+	t.mu.Unlock()
+
+	if ok {
+		h.Stop()
+	}
+}
+
+// remove forgets h unless uuid is tracked by another handle since.
+func (t *BundleTracker) remove(h *BundleHandle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.handles[h.UUID] == h {
+		delete(t.handles, h.UUID)
+	}
+}
+
+// Done is closed once the tracker stopped reading the stream.
+func (t *BundleTracker) Done() <-chan struct{} {
+	return t.done
+}
+
+// Err returns the reason the tracker stopped, nil while it is still running.
+func (t *BundleTracker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *BundleTracker) run(ctx context.Context) {
+	defer close(t.done)
+
+	results := make(chan *proto.BundleResult)
+	errCh := make(chan error, 1)
+
+	go func() {
+		for {
+			result, err := t.stream.Recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.close(ctx.Err())
+			return
+		case err := <-errCh:
+			t.close(err)
+			return
+		case result := <-results:
+			t.route(result)
+		}
+	}
+}
+
+func (t *BundleTracker) route(result *proto.BundleResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if h, ok := t.handles[result.GetBundleId()]; ok {
+		t.dispatch(h, result)
+		return
+	}
+
+	now := time.Now()
+	for id, orphan := range t.orphans {
+		if now.Sub(orphan.received) > orphanTTL {
+			delete(t.orphans, id)
+		}
+	}
+
+	orphan, ok := t.orphans[result.GetBundleId()]
+	if !ok {
+		orphan = &orphanResults{}
+		t.orphans[result.GetBundleId()] = orphan
+	}
+	orphan.results = append(orphan.results, result)
+	orphan.received = now
+}
+
+// dispatch must be called with t.mu held.
+func (t *BundleTracker) dispatch(h *BundleHandle, result *proto.BundleResult) {
+	final := IsFinalBundleResult(result)
+	h.push(result, final)
+	if final {
+		delete(t.handles, h.UUID)
+	}
+}
+
+func (t *BundleTracker) close(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.err = err
+	for uuid, h := range t.handles {
+		h.fail(err)
+		delete(t.handles, uuid)
+	}
+}
+
+// IsFinalBundleResult reports whether no further results are expected for the bundle after this one.
+func IsFinalBundleResult(result *proto.BundleResult) bool {
+	switch result.GetResult().(type) {
+	case *proto.BundleResult_Rejected, *proto.BundleResult_Finalized, *proto.BundleResult_Dropped:
+		return true
+	default:
+		return false
+	}
+}
+
+// BundleHandle delivers, in order, the results of a single bundle.
+type BundleHandle struct {
+	UUID string
+
+	// tracker routes the results to the handle, nil when it was closed before Track
+	tracker *BundleTracker
+	results chan *proto.BundleResult
+	notify  chan struct{}
+	quit    chan struct{}
+	stop    sync.Once
+
+	mu     sync.Mutex
+	queue  []*proto.BundleResult
+	closed bool
+	err    error
+}
+
+func newBundleHandle(uuid string) *BundleHandle {
+	h := &BundleHandle{
+		UUID:    uuid,
+		results: make(chan *proto.BundleResult),
+		notify:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+
+	go h.run()
+
+	return h
+}
+
+// Results streams every state of the bundle (Accepted, Rejected, Processed, Finalized, Dropped) in the order
+// the block engine emitted them. The channel is closed after the final result or when the tracker stops.
+func (h *BundleHandle) Results() <-chan *proto.BundleResult {
+	return h.results
+}
+
+// Wait consumes the results until the bundle reaches a final state and returns the last result received.
+func (h *BundleHandle) Wait(ctx context.Context) (*proto.BundleResult, error) {
+	var last *proto.BundleResult
+	for {
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case result, ok := <-h.results:
+			if !ok {
+				return last, h.Err()
+			}
+			last = result
+		}
+	}
+}
+
+// Err returns why the results channel was closed before the bundle reached a final state, nil otherwise.
+func (h *BundleHandle) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// Stop releases the handle without waiting for the remaining results, its tracker stops routing them.
+func (h *BundleHandle) Stop() {
+	if h.tracker != nil {
+		h.tracker.remove(h)
+	}
+	h.fail(ErrBundleUntracked)
+	h.stop.Do(func() { close(h.quit) })
+}
+
+func (h *BundleHandle) push(result *proto.BundleResult, final bool) {
+	h.mu.Lock()
+	if !h.closed {
+		h.queue = append(h.queue, result)
+		h.closed = final
+	}
+	h.mu.Unlock()
+
+	h.signal()
+}
+
+func (h *BundleHandle) fail(err error) {
+	h.mu.Lock()
+	if !h.closed {
+		if err == nil {
+			err = ErrTrackerClosed
+		}
+		h.err = err
+		h.closed = true
+	}
+	h.mu.Unlock()
+
+	h.signal()
+}
+
+func (h *BundleHandle) signal() {
+	select {
+	case h.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (h *BundleHandle) run() {
+	defer close(h.results)
+
+	for {
+		h.mu.Lock()
+		if len(h.queue) == 0 {
+			closed := h.closed
+			h.mu.Unlock()
+			if closed {
+				return
+			}
+
+			select {
+			case <-h.notify:
+				continue
+			case <-h.quit:
+				return
+			}
+		}
+
+		result := h.queue[0]
+		h.queue = h.queue[1:]
+		h.mu.Unlock()
+
+		select {
+		case h.results <- result:
+		case <-h.quit:
+			return
+		}
+	}
+}
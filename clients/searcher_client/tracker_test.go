@@ -0,0 +1,147 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type fakeBundleResultsStream struct {
+	grpc.ClientStream
+	results chan *proto.BundleResult
+	err     chan error
+}
+
+func newFakeBundleResultsStream() *fakeBundleResultsStream {
+	return &fakeBundleResultsStream{
+		results: make(chan *proto.BundleResult, 16),
+		err:     make(chan error, 1),
+	}
+}
+
+func (s *fakeBundleResultsStream) Recv() (*proto.BundleResult, error) {
+	select {
+	case result := <-s.results:
+		return result, nil
+	case err := <-s.err:
+		return nil, err
+	}
+}
+
+func acceptedResult(id string, slot uint64) *proto.BundleResult {
+	return &proto.BundleResult{BundleId: id, Result: &proto.BundleResult_Accepted{Accepted: &proto.Accepted{Slot: slot}}}
+}
+
+func processedResult(id string, slot uint64) *proto.BundleResult {
+	return &proto.BundleResult{BundleId: id, Result: &proto.BundleResult_Processed{Processed: &proto.Processed{Slot: slot}}}
+}
+
+func finalizedResult(id string) *proto.BundleResult {
+	return &proto.BundleResult{BundleId: id, Result: &proto.BundleResult_Finalized{Finalized: &proto.Finalized{}}}
+}
+
+func Test_BundleTracker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("RoutesResultsInOrder", func(t *testing.T) {
+		stream := newFakeBundleResultsStream()
+		tracker := NewBundleTracker(ctx, stream)
+
+		a := tracker.Track("a")
+		b := tracker.Track("b")
+
+		stream.results <- acceptedResult("a", 1)
+		stream.results <- acceptedResult("b", 2)
+		stream.results <- processedResult("a", 1)
+		stream.results <- finalizedResult("b")
+		stream.results <- finalizedResult("a")
+
+		var got []*proto.BundleResult
+		for result := range a.Results() {
+			got = append(got, result)
+		}
+		if !assert.Len(t, got, 3) {
+			t.FailNow()
+		}
+		assert.NotNil(t, got[0].GetAccepted())
+		assert.NotNil(t, got[1].GetProcessed())
+		assert.NotNil(t, got[2].GetFinalized())
+		assert.NoError(t, a.Err())
+
+		last, err := b.Wait(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", last.BundleId)
+		assert.NotNil(t, last.GetFinalized())
+	})
+
+	t.Run("ReplaysResultsReceivedBeforeTrack", func(t *testing.T) {
+		stream := newFakeBundleResultsStream()
+		tracker := NewBundleTracker(ctx, stream)
+
+		stream.results <- acceptedResult("early", 7)
+		// a result for a tracked bundle guarantees the previous one was routed
+		marker := tracker.Track("sync")
+		stream.results <- acceptedResult("sync", 1)
+		<-marker.Results()
+
+		h := tracker.Track("early")
+		result := <-h.Results()
+		assert.Equal(t, uint64(7), result.GetAccepted().GetSlot())
+	})
+
+	t.Run("StreamFailureClosesHandles", func(t *testing.T) {
+		stream := newFakeBundleResultsStream()
+		tracker := NewBundleTracker(ctx, stream)
+
+		h := tracker.Track("a")
+		streamErr := errors.New("stream broken")
+		stream.err <- streamErr
+
+		_, err := h.Wait(ctx)
+		assert.ErrorIs(t, err, streamErr)
+
+		<-tracker.Done()
+		assert.ErrorIs(t, tracker.Err(), streamErr)
+		assert.ErrorIs(t, tracker.Track("b").Err(), streamErr)
+	})
+
+	t.Run("Untrack", func(t *testing.T) {
+		stream := newFakeBundleResultsStream()
+		tracker := NewBundleTracker(ctx, stream)
+
+		h := tracker.Track("a")
+		tracker.Untrack("a")
+
+		_, err := h.Wait(ctx)
+		assert.ErrorIs(t, err, ErrBundleUntracked)
+	})
+
+	t.Run("StopReleasesHandle", func(t *testing.T) {
+		stream := newFakeBundleResultsStream()
+		tracker := NewBundleTracker(ctx, stream)
+
+		h := tracker.Track("a")
+		h.Stop()
+		h.Stop()
+
+		tracker.mu.Lock()
+		assert.Empty(t, tracker.handles)
+		tracker.mu.Unlock()
+
+		// tracking the bundle again gets a fresh handle receiving the later results
+		again := tracker.Track("a")
+		assert.NotSame(t, h, again)
+		h.Stop()
+
+		stream.results <- finalizedResult("a")
+		result, err := again.Wait(ctx)
+		assert.NoError(t, err)
+		assert.NotNil(t, result.GetFinalized())
+	})
+}
@@ -0,0 +1,208 @@
+package searcher_client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// PacketMTU is the maximum size of a serialized transaction.
+const PacketMTU = 1232
+
+var (
+	ErrPacketTooLarge     = fmt.Errorf("transaction exceeds %d bytes", PacketMTU)
+	ErrInvalidTransaction = errors.New("transaction cannot be decoded")
+	ErrMissingSignature   = errors.New("transaction is missing signatures")
+	ErrInvalidSignature   = errors.New("transaction has an invalid signature")
+	ErrDuplicateSignature = errors.New("transaction signature is duplicated in the bundle")
+	ErrMissingTipTransfer = errors.New("bundle has no transfer to a tip account")
+)
+
+// NoTransactionIndex is the BundleValidationError index of failures concerning the whole bundle.
+const NoTransactionIndex = -1
+
+// BundleValidationError is a pre-flight validation failure. Index is the offending transaction, or NoTransactionIndex.
+// It wraps one of the validation sentinels, e.g. ErrPacketTooLarge.
+type BundleValidationError struct {
+	Index  int
+	Reason error
+	Detail string
+}
+
+func (e *BundleValidationError) Error() string {
+	msg := e.Reason.Error()
+	if e.Detail != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Detail)
+	}
+
+	if e.Index == NoTransactionIndex {
+		return msg
+	}
+
+	return fmt.Sprintf("transaction %d: %s", e.Index, msg)
+}
+
+func (e *BundleValidationError) Unwrap() error {
+	return e.Reason
+}
+
+// BundleValidator rejects bundles the block engine would refuse, before they are sent.
+type BundleValidator struct {
+	tipAccounts map[solana.PublicKey]struct{}
+
+	// TipAccountsFunc returns tip accounts accepted on top of the ones provided to NewBundleValidator.
+	TipAccountsFunc func() []solana.PublicKey
+	// SkipTipCheck disables the tip transfer check.
+	SkipTipCheck bool
+}
+
+// NewBundleValidator creates a BundleValidator accepting transfers to the provided tip accounts,
+// e.g. jito_go.MainnetTipAccounts and jito_go.TestnetTipAccounts.
+func NewBundleValidator(tipAccounts ...[]solana.PublicKey) *BundleValidator {
+	v := &BundleValidator{tipAccounts: make(map[solana.PublicKey]struct{})}
+	for _, accounts := range tipAccounts {
+		for _, account := range accounts {
+			v.tipAccounts[account] = struct{}{}
+		}
+	}
+
+	return v
+}
+
+// Validate checks the bundle and returns every failure found, joined. Each failure is a *BundleValidationError.
+func (v *BundleValidator) Validate(bundle *proto.Bundle) error {
+	packets := bundle.GetPackets()
+	if len(packets) == 0 {
+		return &BundleValidationError{Index: NoTransactionIndex, Reason: ErrEmptyBundle}
+	}
+
+	var errs []error
+	if len(packets) > MaxBundleTransactions {
+		errs = append(errs, &BundleValidationError{
+			Index:  NoTransactionIndex,
+			Reason: ErrTooManyTransactions,
+			Detail: fmt.Sprintf("got %d", len(packets)),
+		})
+	}
+
+	tipAccounts := v.acceptedTipAccounts()
+	signatures := make(map[solana.Signature]int, len(packets))
+	tipped := false
+
+	for i, packet := range packets {
+		if len(packet.GetData()) > PacketMTU {
+			errs = append(errs, &BundleValidationError{Index: i, Reason: ErrPacketTooLarge, Detail: fmt.Sprintf("got %d bytes", len(packet.GetData()))})
+		}
+
+		tx, err := pkg.ConvertProtobufPacketToTransaction(packet)
+		if err != nil {
+			errs = append(errs, &BundleValidationError{Index: i, Reason: ErrInvalidTransaction, Detail: err.Error()})
+			continue
+		}
+
+		if err = checkSignatures(tx); err != nil {
+			errs = append(errs, &BundleValidationError{Index: i, Reason: err})
+		} else if first, ok := signatures[tx.Signatures[0]]; ok {
+			errs = append(errs, &BundleValidationError{Index: i, Reason: ErrDuplicateSignature, Detail: fmt.Sprintf("same as transaction %d", first)})
+		} else {
+			signatures[tx.Signatures[0]] = i
+		}
+
+		if !tipped && !v.SkipTipCheck {
+			tipped = hasTipTransfer(tx, tipAccounts)
+		}
+	}
+
+	if !tipped && !v.SkipTipCheck {
+		errs = append(errs, &BundleValidationError{Index: NoTransactionIndex, Reason: ErrMissingTipTransfer})
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateTransactions is like Validate for transactions not yet assembled in a bundle.
+func (v *BundleValidator) ValidateTransactions(transactions []pkg.Transaction) error {
+	packets, err := assemblePackets(transactions)
+	if err != nil {
+		return err
+	}
+
+	return v.Validate(&proto.Bundle{Packets: packets})
+}
+
+func (v *BundleValidator) acceptedTipAccounts() map[solana.PublicKey]struct{} {
+	if v.TipAccountsFunc == nil {
+		return v.tipAccounts
+	}
+
+	accounts := make(map[solana.PublicKey]struct{}, len(v.tipAccounts))
+	for account := range v.tipAccounts {
+		accounts[account] = struct{}{}
+	}
+	for _, account := range v.TipAccountsFunc() {
+		accounts[account] = struct{}{}
+	}
+
+	return accounts
+}
+
+func checkSignatures(tx *solana.Transaction) error {
+	required := int(tx.Message.Header.NumRequiredSignatures)
+	if required == 0 || len(tx.Signatures) < required {
+		return ErrMissingSignature
+	}
+
+	for _, sig := range tx.Signatures {
+		if sig.IsZero() {
+			return ErrMissingSignature
+		}
+	}
+
+	if err := tx.VerifySignatures(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
+
+// hasTipTransfer reports whether tx holds a system transfer to one of tipAccounts.
+// Only static account keys are considered, as tip accounts must not be loaded thru address lookup tables.
+func hasTipTransfer(tx *solana.Transaction, tipAccounts map[solana.PublicKey]struct{}) bool {
+	keys := tx.Message.AccountKeys
+	for _, inst := range tx.Message.Instructions {
+		if int(inst.ProgramIDIndex) >= len(keys) || !keys[inst.ProgramIDIndex].Equals(solana.SystemProgramID) {
+			continue
+		}
+
+		accounts := make([]*solana.AccountMeta, 0, len(inst.Accounts))
+		for _, index := range inst.Accounts {
+			if int(index) >= len(keys) {
+				break
+			}
+			accounts = append(accounts, solana.Meta(keys[index]))
+		}
+		if len(accounts) != len(inst.Accounts) {
+			continue
+		}
+
+		decoded, err := system.DecodeInstruction(accounts, inst.Data)
+		if err != nil {
+			continue
+		}
+
+		transfer, ok := decoded.Impl.(*system.Transfer)
+		if !ok {
+			continue
+		}
+
+		if _, ok = tipAccounts[transfer.GetRecipientAccount().PublicKey]; ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package searcher_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/pvaronik/jito-go"
+	"github.com/pvaronik/jito-go/pkg"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// validationErrors returns the *BundleValidationError joined in err.
+func validationErrors(err error) []*BundleValidationError {
+	var out []*BundleValidationError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			out = append(out, validationErrors(e)...)
+		}
+		return out
+	}
+
+	var validationErr *BundleValidationError
+	if errors.As(err, &validationErr) {
+		out = append(out, validationErr)
+	}
+	return out
+}
+
+func Test_BundleValidator(t *testing.T) {
+	ctx := context.Background()
+	validator := NewBundleValidator(jito_go.MainnetTipAccounts, jito_go.TestnetTipAccounts)
+	payer := solana.NewWallet().PrivateKey
+
+	build := func(t *testing.T, tip bool, count int) []*solana.Transaction {
+		builder := (&Client{}).NewBundleBuilder().WithSigners(payer).WithBlockhash(solana.Hash{1})
+		for i := 0; i < count; i++ {
+			builder.AddInstructions(system.NewTransferInstruction(uint64(i+1), payer.PublicKey(), solana.NewWallet().PublicKey()).Build())
+		}
+		if tip {
+			builder.WithTip(1000).WithTipAccount(jito_go.TestnetTipAccounts[2])
+		}
+
+		txns, err := builder.BuildTransactions(ctx)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return txns
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, validator.ValidateTransactions(pkg.Transactions(build(t, true, 3))))
+	})
+
+	t.Run("MissingTip", func(t *testing.T) {
+		err := validator.ValidateTransactions(pkg.Transactions(build(t, false, 1)))
+		assert.ErrorIs(t, err, ErrMissingTipTransfer)
+
+		noTipCheck := NewBundleValidator()
+		noTipCheck.SkipTipCheck = true
+		assert.NoError(t, noTipCheck.ValidateTransactions(pkg.Transactions(build(t, false, 1))))
+	})
+
+	t.Run("TipAccountsFunc", func(t *testing.T) {
+		custom := NewBundleValidator()
+		assert.ErrorIs(t, custom.ValidateTransactions(pkg.Transactions(build(t, true, 1))), ErrMissingTipTransfer)
+
+		custom.TipAccountsFunc = func() []solana.PublicKey { return jito_go.TestnetTipAccounts }
+		assert.NoError(t, custom.ValidateTransactions(pkg.Transactions(build(t, true, 1))))
+	})
+
+	t.Run("TooManyTransactions", func(t *testing.T) {
+		txns := build(t, true, 5)
+		txns = append(txns, build(t, false, 1)...)
+
+		err := validator.ValidateTransactions(pkg.Transactions(txns))
+		assert.ErrorIs(t, err, ErrTooManyTransactions)
+		if errs := validationErrors(err); assert.Len(t, errs, 1) {
+			assert.Equal(t, NoTransactionIndex, errs[0].Index)
+		}
+	})
+
+	t.Run("SignatureFailures", func(t *testing.T) {
+		txns := build(t, true, 5)
+
+		txns[1].Signatures[0] = solana.Signature{}
+		txns[2].Signatures[0][0] ^= 0xff
+		txns[3] = txns[0]
+
+		errs := validationErrors(validator.ValidateTransactions(pkg.Transactions(txns)))
+		if !assert.Len(t, errs, 3) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, 1, errs[0].Index)
+		assert.ErrorIs(t, errs[0], ErrMissingSignature)
+		assert.Equal(t, 2, errs[1].Index)
+		assert.ErrorIs(t, errs[1], ErrInvalidSignature)
+		assert.Equal(t, 3, errs[2].Index)
+		assert.ErrorIs(t, errs[2], ErrDuplicateSignature)
+	})
+
+	t.Run("PacketTooLarge", func(t *testing.T) {
+		packets, err := pkg.ConvertBatchTransactionToProtobufPacket(pkg.Transactions(build(t, true, 1)))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		packets = append(packets, &proto.Packet{Data: make([]byte, PacketMTU+1)})
+
+		errs := validationErrors(validator.Validate(&proto.Bundle{Packets: packets}))
+		if !assert.Len(t, errs, 2) {
+			t.FailNow()
+		}
+		assert.Equal(t, 1, errs[0].Index)
+		assert.ErrorIs(t, errs[0], ErrPacketTooLarge)
+		assert.Equal(t, 1, errs[1].Index)
+	})
+
+	t.Run("SendBundleRejectsInvalidBundle", func(t *testing.T) {
+		client := &Client{Auth: &pkg.AuthenticationService{}, Validator: validator}
+		_, err := client.SendBundle(&proto.Bundle{})
+		assert.ErrorIs(t, err, ErrEmptyBundle)
+	})
+}
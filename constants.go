@@ -93,3 +93,6 @@ var TestnetDallas = JitoEndpoints["BigD-TESTNET"]
 var TestnetNewYork = JitoEndpoints["NY-TESTNET"]
 
 const JitoMainnet = "mainnet.rpc.jito.wtf"
+
+// JitoBlockEngineMainnet is the mainnet block engine host of the JSON-RPC bundles API, regional hosts are the BlockEngineURL of JitoEndpoints.
+const JitoBlockEngineMainnet = "mainnet.block-engine.jito.wtf"
@@ -0,0 +1,560 @@
+package pkg
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// AnchorDiscriminatorSize is the size of the discriminator prefixing the Anchor instructions and accounts data.
+const AnchorDiscriminatorSize = 8
+
+var (
+	ErrUnknownDiscriminator = errors.New("unknown anchor discriminator")
+	ErrUnknownIdlType       = errors.New("unknown anchor idl type")
+	ErrTrailingData         = errors.New("data left after decoding")
+)
+
+// AnchorIDL is an Anchor IDL, in the legacy format or in the one of Anchor 0.30 and later.
+// It decodes the instructions and accounts of its program by discriminator.
+type AnchorIDL struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Instructions []IdlInstruction `json:"instructions"`
+	Accounts     []IdlAccount     `json:"accounts"`
+	Types        []IdlTypeDef     `json:"types"`
+
+	instructions map[[AnchorDiscriminatorSize]byte]*IdlInstruction
+	accounts     map[[AnchorDiscriminatorSize]byte]*IdlAccount
+	types        map[string]*IdlTypeDef
+}
+
+type IdlInstruction struct {
+	Name string `json:"name"`
+	// Discriminator is set by the IDL since Anchor 0.30, it is derived from Name for the legacy ones.
+	Discriminator []byte                  `json:"discriminator"`
+	Accounts      []IdlInstructionAccount `json:"accounts"`
+	Args          []IdlField              `json:"args"`
+}
+
+// IdlInstructionAccount is an account of an instruction, or a group of accounts when Accounts is set.
+type IdlInstructionAccount struct {
+	Name     string                  `json:"name"`
+	Accounts []IdlInstructionAccount `json:"accounts"`
+}
+
+type IdlAccount struct {
+	Name          string `json:"name"`
+	Discriminator []byte `json:"discriminator"`
+	// Type is set by the legacy IDL, the type of the account is in Types since Anchor 0.30.
+	Type *IdlTypeDefTy `json:"type"`
+}
+
+type IdlTypeDef struct {
+	Name string       `json:"name"`
+	Type IdlTypeDefTy `json:"type"`
+}
+
+// IdlTypeDefTy is a struct, an enum, or an alias of another type.
+type IdlTypeDefTy struct {
+	Kind     string       `json:"kind"`
+	Fields   IdlFields    `json:"fields"`
+	Variants []IdlVariant `json:"variants"`
+	Alias    *IdlType     `json:"alias"`
+}
+
+type IdlVariant struct {
+	Name   string    `json:"name"`
+	Fields IdlFields `json:"fields"`
+}
+
+type IdlField struct {
+	Name string  `json:"name"`
+	Type IdlType `json:"type"`
+}
+
+// IdlFields are the fields of a struct or an enum variant, their names are empty for tuples.
+type IdlFields []IdlField
+
+func (f *IdlFields) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fields := make(IdlFields, len(raw))
+	for i, r := range raw {
+		var named struct {
+			Name *string         `json:"name"`
+			Type json.RawMessage `json:"type"`
+		}
+		if json.Unmarshal(r, &named) == nil && named.Name != nil && named.Type != nil {
+			fields[i].Name = *named.Name
+			r = named.Type
+		}
+		if err := json.Unmarshal(r, &fields[i].Type); err != nil {
+			return err
+		}
+	}
+
+	*f = fields
+	return nil
+}
+
+// IdlType is a primitive type, e.g. "u64" or "pubkey", or one of the vec, option, coption, array and defined types.
+type IdlType struct {
+	Primitive string
+	Vec       *IdlType
+	Option    *IdlType
+	COption   *IdlType
+	Array     *IdlType
+	ArrayLen  int
+	Defined   string
+}
+
+func (t *IdlType) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Primitive); err == nil {
+		return nil
+	}
+
+	var compound struct {
+		Vec     *IdlType          `json:"vec"`
+		Option  *IdlType          `json:"option"`
+		COption *IdlType          `json:"coption"`
+		Array   []json.RawMessage `json:"array"`
+		Defined json.RawMessage   `json:"defined"`
+	}
+	if err := json.Unmarshal(data, &compound); err != nil {
+		return err
+	}
+	t.Vec, t.Option, t.COption = compound.Vec, compound.Option, compound.COption
+
+	if len(compound.Array) == 2 {
+		t.Array = new(IdlType)
+		if err := json.Unmarshal(compound.Array[0], t.Array); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(compound.Array[1], &t.ArrayLen); err != nil {
+			return fmt.Errorf("%w: array length %s", ErrUnknownIdlType, compound.Array[1])
+		}
+	}
+
+	if compound.Defined != nil {
+		// the legacy IDL names the type, the later ones wrap the name with its generics
+		if err := json.Unmarshal(compound.Defined, &t.Defined); err != nil {
+			var defined struct {
+				Name string `json:"name"`
+			}
+			if err = json.Unmarshal(compound.Defined, &defined); err != nil {
+				return err
+			}
+			t.Defined = defined.Name
+		}
+	}
+
+	if t.Vec == nil && t.Option == nil && t.COption == nil && t.Array == nil && t.Defined == "" {
+		return fmt.Errorf("%w: %s", ErrUnknownIdlType, data)
+	}
+	return nil
+}
+
+// AnchorInstruction is an instruction decoded with an AnchorIDL.
+type AnchorInstruction struct {
+	Name string
+	Args map[string]interface{}
+	// Accounts are the accounts of the instruction by name, the groups of accounts are flattened.
+	Accounts map[string]*solana.AccountMeta
+}
+
+// AnchorEnum is a decoded enum, Fields is nil for unit variants, a map for named fields and a slice for tuples.
+type AnchorEnum struct {
+	Variant string
+	Fields  interface{}
+}
+
+// LoadAnchorIDL reads the Anchor IDL JSON file at path.
+func LoadAnchorIDL(path string) (*AnchorIDL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAnchorIDL(data)
+}
+
+// ParseAnchorIDL parses an Anchor IDL JSON.
+func ParseAnchorIDL(data []byte) (*AnchorIDL, error) {
+	idl := new(AnchorIDL)
+	if err := json.Unmarshal(data, idl); err != nil {
+		return nil, err
+	}
+
+	idl.types = make(map[string]*IdlTypeDef, len(idl.Types))
+	for i := range idl.Types {
+		idl.types[idl.Types[i].Name] = &idl.Types[i]
+	}
+
+	idl.instructions = make(map[[AnchorDiscriminatorSize]byte]*IdlInstruction, len(idl.Instructions))
+	for i := range idl.Instructions {
+		instruction := &idl.Instructions[i]
+		if instruction.Discriminator == nil {
+			instruction.Discriminator = bin.SighashInstruction(instruction.Name)
+		}
+		if len(instruction.Discriminator) != AnchorDiscriminatorSize {
+			return nil, fmt.Errorf("instruction %s: discriminator of %d bytes", instruction.Name, len(instruction.Discriminator))
+		}
+		idl.instructions[[AnchorDiscriminatorSize]byte(instruction.Discriminator)] = instruction
+	}
+
+	idl.accounts = make(map[[AnchorDiscriminatorSize]byte]*IdlAccount, len(idl.Accounts))
+	for i := range idl.Accounts {
+		account := &idl.Accounts[i]
+		if account.Discriminator == nil {
+			account.Discriminator = bin.SighashAccount(account.Name)
+		}
+		if len(account.Discriminator) != AnchorDiscriminatorSize {
+			return nil, fmt.Errorf("account %s: discriminator of %d bytes", account.Name, len(account.Discriminator))
+		}
+		if account.Type == nil {
+			typeDef, ok := idl.types[account.Name]
+			if !ok {
+				return nil, fmt.Errorf("%w: account %s", ErrUnknownIdlType, account.Name)
+			}
+			account.Type = &typeDef.Type
+		}
+		idl.accounts[[AnchorDiscriminatorSize]byte(account.Discriminator)] = account
+	}
+
+	return idl, nil
+}
+
+// ProgramName returns the name of the program of the IDL.
+func (idl *AnchorIDL) ProgramName() string {
+	if idl.Metadata.Name != "" {
+		return idl.Metadata.Name
+	}
+	return idl.Name
+}
+
+// Register registers the instruction decoder of the IDL to registry for programID.
+func (idl *AnchorIDL) Register(registry *InstructionRegistry, programID solana.PublicKey) {
+	registry.Register(programID, idl.ProgramName(), idl.InstructionDecoder())
+}
+
+// InstructionDecoder returns an InstructionDecoder decoding the instructions to *AnchorInstruction.
+func (idl *AnchorIDL) InstructionDecoder() InstructionDecoder {
+	return func(accounts []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+		instruction, err := idl.DecodeInstruction(accounts, data)
+		if err != nil {
+			return "", nil, err
+		}
+		return instruction.Name, instruction, nil
+	}
+}
+
+// DecodeInstruction decodes the instruction identified by the discriminator of data.
+func (idl *AnchorIDL) DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*AnchorInstruction, error) {
+	definition, err := idl.instruction(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := bin.NewBorshDecoder(data[AnchorDiscriminatorSize:])
+	// args are always named, even if the IDL leaves their names empty
+	args, err := idl.decodeNamedFields(decoder, definition.Args)
+	if err != nil {
+		return nil, fmt.Errorf("instruction %s: %w", definition.Name, err)
+	}
+	if decoder.HasRemaining() {
+		return nil, fmt.Errorf("instruction %s: %w", definition.Name, ErrTrailingData)
+	}
+
+	instruction := &AnchorInstruction{Name: definition.Name, Args: args, Accounts: make(map[string]*solana.AccountMeta)}
+	names := flattenAccounts(definition.Accounts, nil)
+	for i := 0; i < len(names) && i < len(accounts); i++ {
+		instruction.Accounts[names[i]] = accounts[i]
+	}
+
+	return instruction, nil
+}
+
+// DecodeInstructionInto decodes the args of the instruction data into v with Borsh, after checking its discriminator.
+// It returns the name of the instruction, and ErrTrailingData when v does not cover every byte like DecodeInstruction.
+func (idl *AnchorIDL) DecodeInstructionInto(data []byte, v interface{}) (string, error) {
+	definition, err := idl.instruction(data)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := bin.NewBorshDecoder(data[AnchorDiscriminatorSize:])
+	if err = decoder.Decode(v); err != nil {
+		return definition.Name, err
+	}
+	if decoder.HasRemaining() {
+		return definition.Name, fmt.Errorf("instruction %s: %w", definition.Name, ErrTrailingData)
+	}
+	return definition.Name, nil
+}
+
+// AccountType returns the name of the account type identified by the discriminator of data.
+func (idl *AnchorIDL) AccountType(data []byte) (string, bool) {
+	account, err := idl.account(data)
+	if err != nil {
+		return "", false
+	}
+	return account.Name, true
+}
+
+// DecodeAccount decodes the account data, its type is detected with the discriminator.
+// Trailing bytes are ignored as accounts are often allocated larger than their type.
+func (idl *AnchorIDL) DecodeAccount(data []byte) (string, map[string]interface{}, error) {
+	account, err := idl.account(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	value, err := idl.decodeTypeDef(bin.NewBorshDecoder(data[AnchorDiscriminatorSize:]), account.Type)
+	if err != nil {
+		return "", nil, fmt.Errorf("account %s: %w", account.Name, err)
+	}
+
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		fields = map[string]interface{}{"": value}
+	}
+	return account.Name, fields, nil
+}
+
+// DecodeAccountUpdate decodes the data of an account streamed by geyser, see DecodeAccount.
+func (idl *AnchorIDL) DecodeAccountUpdate(update *proto.AccountUpdate) (string, map[string]interface{}, error) {
+	return idl.DecodeAccount(update.GetData())
+}
+
+// DecodeAccountInto decodes the account data into v with Borsh, after checking it is an account of type name.
+func (idl *AnchorIDL) DecodeAccountInto(name string, data []byte, v interface{}) error {
+	account, err := idl.account(data)
+	if err != nil {
+		return err
+	}
+	if account.Name != name {
+		return fmt.Errorf("account is a %s, not a %s", account.Name, name)
+	}
+	return bin.NewBorshDecoder(data[AnchorDiscriminatorSize:]).Decode(v)
+}
+
+func (idl *AnchorIDL) instruction(data []byte) (*IdlInstruction, error) {
+	if len(data) < AnchorDiscriminatorSize {
+		return nil, fmt.Errorf("%w: %d bytes of data", ErrUnknownDiscriminator, len(data))
+	}
+
+	instruction, ok := idl.instructions[[AnchorDiscriminatorSize]byte(data)]
+	if !ok {
+		return nil, fmt.Errorf("%w: instruction %x", ErrUnknownDiscriminator, data[:AnchorDiscriminatorSize])
+	}
+	return instruction, nil
+}
+
+func (idl *AnchorIDL) account(data []byte) (*IdlAccount, error) {
+	if len(data) < AnchorDiscriminatorSize {
+		return nil, fmt.Errorf("%w: %d bytes of data", ErrUnknownDiscriminator, len(data))
+	}
+
+	account, ok := idl.accounts[[AnchorDiscriminatorSize]byte(data)]
+	if !ok {
+		return nil, fmt.Errorf("%w: account %x", ErrUnknownDiscriminator, data[:AnchorDiscriminatorSize])
+	}
+	return account, nil
+}
+
+func flattenAccounts(accounts []IdlInstructionAccount, names []string) []string {
+	for _, account := range accounts {
+		if account.Accounts != nil {
+			names = flattenAccounts(account.Accounts, names)
+		} else {
+			names = append(names, account.Name)
+		}
+	}
+	return names
+}
+
+// decodeFields decodes a map for named fields and a slice for tuples.
+func (idl *AnchorIDL) decodeFields(decoder *bin.Decoder, fields IdlFields) (interface{}, error) {
+	if len(fields) != 0 && fields[0].Name == "" {
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			value, err := idl.decodeType(decoder, &field.Type)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+
+	return idl.decodeNamedFields(decoder, fields)
+}
+
+func (idl *AnchorIDL) decodeNamedFields(decoder *bin.Decoder, fields IdlFields) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, err := idl.decodeType(decoder, &field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+func (idl *AnchorIDL) decodeTypeDef(decoder *bin.Decoder, ty *IdlTypeDefTy) (interface{}, error) {
+	switch ty.Kind {
+	case "struct":
+		return idl.decodeFields(decoder, ty.Fields)
+	case "enum":
+		index, err := decoder.ReadUint8()
+		if err != nil {
+			return nil, err
+		}
+		if int(index) >= len(ty.Variants) {
+			return nil, fmt.Errorf("enum variant %d out of range", index)
+		}
+
+		variant := ty.Variants[index]
+		enum := &AnchorEnum{Variant: variant.Name}
+		if len(variant.Fields) != 0 {
+			if enum.Fields, err = idl.decodeFields(decoder, variant.Fields); err != nil {
+				return nil, fmt.Errorf("%s: %w", variant.Name, err)
+			}
+		}
+		return enum, nil
+	case "type":
+		if ty.Alias == nil {
+			return nil, fmt.Errorf("%w: alias without type", ErrUnknownIdlType)
+		}
+		return idl.decodeType(decoder, ty.Alias)
+	default:
+		return nil, fmt.Errorf("%w: kind %q", ErrUnknownIdlType, ty.Kind)
+	}
+}
+
+func (idl *AnchorIDL) decodeType(decoder *bin.Decoder, ty *IdlType) (interface{}, error) {
+	switch {
+	case ty.Vec != nil:
+		length, err := decoder.ReadUint32(binary.LittleEndian)
+		if err != nil {
+			return nil, err
+		}
+		if ty.Vec.Primitive == "u8" {
+			return decoder.ReadNBytes(int(length))
+		}
+		return idl.decodeSequence(decoder, ty.Vec, int(length))
+	case ty.Array != nil:
+		if ty.Array.Primitive == "u8" {
+			return decoder.ReadNBytes(ty.ArrayLen)
+		}
+		return idl.decodeSequence(decoder, ty.Array, ty.ArrayLen)
+	case ty.Option != nil:
+		some, err := decoder.ReadUint8()
+		if err != nil || some == 0 {
+			return nil, err
+		}
+		return idl.decodeType(decoder, ty.Option)
+	case ty.COption != nil:
+		some, err := decoder.ReadUint32(binary.LittleEndian)
+		if err != nil || some == 0 {
+			return nil, err
+		}
+		return idl.decodeType(decoder, ty.COption)
+	case ty.Defined != "":
+		typeDef, ok := idl.types[ty.Defined]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownIdlType, ty.Defined)
+		}
+		return idl.decodeTypeDef(decoder, &typeDef.Type)
+	default:
+		return decodePrimitive(decoder, ty.Primitive)
+	}
+}
+
+func (idl *AnchorIDL) decodeSequence(decoder *bin.Decoder, ty *IdlType, length int) ([]interface{}, error) {
+	// every element takes at least a byte, which bounds the allocation of corrupted lengths
+	if length > decoder.Remaining() {
+		return nil, fmt.Errorf("sequence of %d elements in %d bytes", length, decoder.Remaining())
+	}
+
+	values := make([]interface{}, length)
+	for i := range values {
+		value, err := idl.decodeType(decoder, ty)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// decodePrimitive decodes the 128 bits integers to *big.Int, and the public keys to solana.PublicKey.
+func decodePrimitive(decoder *bin.Decoder, primitive string) (interface{}, error) {
+	switch primitive {
+	case "bool":
+		return decoder.ReadBool()
+	case "u8":
+		return decoder.ReadUint8()
+	case "i8":
+		return decoder.ReadInt8()
+	case "u16":
+		return decoder.ReadUint16(binary.LittleEndian)
+	case "i16":
+		return decoder.ReadInt16(binary.LittleEndian)
+	case "u32":
+		return decoder.ReadUint32(binary.LittleEndian)
+	case "i32":
+		return decoder.ReadInt32(binary.LittleEndian)
+	case "f32":
+		return decoder.ReadFloat32(binary.LittleEndian)
+	case "u64":
+		return decoder.ReadUint64(binary.LittleEndian)
+	case "i64":
+		return decoder.ReadInt64(binary.LittleEndian)
+	case "f64":
+		return decoder.ReadFloat64(binary.LittleEndian)
+	case "u128":
+		value, err := decoder.ReadUint128(binary.LittleEndian)
+		if err != nil {
+			return nil, err
+		}
+		return value.BigInt(), nil
+	case "i128":
+		value, err := decoder.ReadInt128(binary.LittleEndian)
+		if err != nil {
+			return nil, err
+		}
+		return value.BigInt(), nil
+	case "string", "bytes":
+		length, err := decoder.ReadUint32(binary.LittleEndian)
+		if err != nil {
+			return nil, err
+		}
+		data, err := decoder.ReadNBytes(int(length))
+		if err != nil || primitive == "bytes" {
+			return data, err
+		}
+		return string(data), nil
+	case "publicKey", "pubkey":
+		key, err := decoder.ReadNBytes(solana.PublicKeyLength)
+		if err != nil {
+			return nil, err
+		}
+		return solana.PublicKeyFromBytes(key), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIdlType, primitive)
+	}
+}
@@ -0,0 +1,239 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+const legacyIDL = `{
+  "version": "0.1.0",
+  "name": "counter",
+  "instructions": [{
+    "name": "incrementBy",
+    "accounts": [
+      {"name": "counter", "isMut": true, "isSigner": false},
+      {"name": "auth", "accounts": [{"name": "authority", "isMut": false, "isSigner": true}]}
+    ],
+    "args": [
+      {"name": "amount", "type": "u64"},
+      {"name": "memo", "type": {"option": "string"}},
+      {"name": "mode", "type": {"defined": "Mode"}}
+    ]
+  }],
+  "accounts": [{
+    "name": "Counter",
+    "type": {"kind": "struct", "fields": [
+      {"name": "authority", "type": "publicKey"},
+      {"name": "count", "type": "u128"},
+      {"name": "history", "type": {"vec": "i32"}},
+      {"name": "seed", "type": {"array": ["u8", 4]}}
+    ]}
+  }],
+  "types": [{
+    "name": "Mode",
+    "type": {"kind": "enum", "variants": [
+      {"name": "Fast"},
+      {"name": "Slow", "fields": [{"name": "delay", "type": "u16"}]},
+      {"name": "Pair", "fields": ["u8", "bool"]}
+    ]}
+  }]
+}`
+
+const anchor030IDL = `{
+  "address": "11111111111111111111111111111111",
+  "metadata": {"name": "vault", "version": "0.1.0", "spec": "0.1.0"},
+  "instructions": [{
+    "name": "deposit",
+    "discriminator": [1, 2, 3, 4, 5, 6, 7, 8],
+    "accounts": [{"name": "user", "writable": true, "signer": true}, {"name": "vault", "writable": true}],
+    "args": [{"name": "amount", "type": "u64"}]
+  }],
+  "accounts": [{"name": "Vault", "discriminator": [8, 7, 6, 5, 4, 3, 2, 1]}],
+  "types": [
+    {"name": "Vault", "type": {"kind": "struct", "fields": [
+      {"name": "owner", "type": "pubkey"},
+      {"name": "balance", "type": "u64"},
+      {"name": "state", "type": {"defined": {"name": "State"}}}
+    ]}},
+    {"name": "State", "type": {"kind": "enum", "variants": [{"name": "Open"}, {"name": "Closed"}]}}
+  ]
+}`
+
+type counterAccount struct {
+	Authority solana.PublicKey
+	Count     bin.Uint128
+	History   []int32
+	Seed      [4]byte
+}
+
+func Test_AnchorIDL(t *testing.T) {
+	legacy, err := ParseAnchorIDL([]byte(legacyIDL))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "counter", legacy.ProgramName())
+
+	authority := solana.NewWallet().PublicKey()
+
+	t.Run("Instruction", func(t *testing.T) {
+		data := append([]byte{}, bin.SighashInstruction("incrementBy")...)
+		data = binary.LittleEndian.AppendUint64(data, 42)
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint32(data, 2)
+		data = append(data, "gm"...)
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint16(data, 500)
+
+		counter := solana.NewWallet().PublicKey()
+		accounts := []*solana.AccountMeta{solana.Meta(counter).WRITE(), solana.Meta(authority).SIGNER()}
+
+		instruction, err := legacy.DecodeInstruction(accounts, data)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "incrementBy", instruction.Name)
+		assert.Equal(t, map[string]interface{}{
+			"amount": uint64(42),
+			"memo":   "gm",
+			"mode":   &AnchorEnum{Variant: "Slow", Fields: map[string]interface{}{"delay": uint16(500)}},
+		}, instruction.Args)
+		assert.Equal(t, counter, instruction.Accounts["counter"].PublicKey)
+		assert.Equal(t, authority, instruction.Accounts["authority"].PublicKey)
+
+		var args struct {
+			Amount uint64
+			Memo   *string `bin:"optional"`
+			Mode   uint8
+			Delay  uint16
+		}
+		name, err := legacy.DecodeInstructionInto(data, &args)
+		assert.NoError(t, err)
+		assert.Equal(t, "incrementBy", name)
+		assert.Equal(t, uint64(42), args.Amount)
+		if assert.NotNil(t, args.Memo) {
+			assert.Equal(t, "gm", *args.Memo)
+		}
+		assert.Equal(t, uint16(500), args.Delay)
+
+		_, err = legacy.DecodeInstructionInto(append(data, 0), &args)
+		assert.ErrorIs(t, err, ErrTrailingData)
+
+		_, err = legacy.DecodeInstruction(accounts, append(data, 0))
+		assert.ErrorIs(t, err, ErrTrailingData)
+		_, err = legacy.DecodeInstruction(accounts, data[:20])
+		assert.Error(t, err)
+		_, err = legacy.DecodeInstruction(accounts, make([]byte, 16))
+		assert.ErrorIs(t, err, ErrUnknownDiscriminator)
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		data := append([]byte{}, bin.SighashInstruction("incrementBy")...)
+		data = binary.LittleEndian.AppendUint64(data, 1)
+		data = append(data, 0, 2, 7, 1)
+
+		program := solana.NewWallet().PublicKey()
+		registry := NewInstructionRegistry()
+		legacy.Register(registry, program)
+
+		decoded := registry.DecodeInstruction(program, nil, data)
+		assert.NoError(t, decoded.Err)
+		assert.Equal(t, "counter", decoded.Program)
+		assert.Equal(t, "incrementBy", decoded.Name)
+		if instruction, ok := decoded.Parsed.(*AnchorInstruction); assert.True(t, ok) {
+			assert.Nil(t, instruction.Args["memo"])
+			assert.Equal(t, &AnchorEnum{Variant: "Pair", Fields: []interface{}{uint8(7), true}}, instruction.Args["mode"])
+		}
+	})
+
+	t.Run("Account", func(t *testing.T) {
+		account := counterAccount{Authority: authority, Count: bin.Uint128{Lo: 5, Hi: 1}, History: []int32{-1, 2}, Seed: [4]byte{1, 2, 3, 4}}
+
+		var buf bytes.Buffer
+		buf.Write(bin.SighashAccount("Counter"))
+		if !assert.NoError(t, bin.NewBorshEncoder(&buf).Encode(account)) {
+			t.FailNow()
+		}
+		// accounts are often allocated larger than their type
+		buf.Write(make([]byte, 16))
+		data := buf.Bytes()
+
+		name, ok := legacy.AccountType(data)
+		assert.True(t, ok)
+		assert.Equal(t, "Counter", name)
+
+		name, fields, err := legacy.DecodeAccountUpdate(&proto.AccountUpdate{Data: data})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "Counter", name)
+		assert.Equal(t, authority, fields["authority"])
+		assert.Equal(t, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(5)), fields["count"])
+		assert.Equal(t, []interface{}{int32(-1), int32(2)}, fields["history"])
+		assert.Equal(t, []byte{1, 2, 3, 4}, fields["seed"])
+
+		var decoded counterAccount
+		assert.NoError(t, legacy.DecodeAccountInto("Counter", data, &decoded))
+		assert.Equal(t, account, decoded)
+		assert.Error(t, legacy.DecodeAccountInto("Vault", data, &decoded))
+
+		_, ok = legacy.AccountType([]byte{1, 2, 3})
+		assert.False(t, ok)
+	})
+
+	t.Run("Anchor030", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vault.json")
+		if !assert.NoError(t, os.WriteFile(path, []byte(anchor030IDL), 0o600)) {
+			t.FailNow()
+		}
+
+		idl, err := LoadAnchorIDL(path)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "vault", idl.ProgramName())
+
+		instruction, err := idl.DecodeInstruction(nil, binary.LittleEndian.AppendUint64([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 10))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "deposit", instruction.Name)
+			assert.Equal(t, uint64(10), instruction.Args["amount"])
+		}
+
+		data := append([]byte{8, 7, 6, 5, 4, 3, 2, 1}, authority.Bytes()...)
+		data = binary.LittleEndian.AppendUint64(data, 99)
+		data = append(data, 1)
+
+		name, fields, err := idl.DecodeAccount(data)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Vault", name)
+			assert.Equal(t, map[string]interface{}{"owner": authority, "balance": uint64(99), "state": &AnchorEnum{Variant: "Closed"}}, fields)
+		}
+	})
+
+	t.Run("UnnamedArgs", func(t *testing.T) {
+		idl, err := ParseAnchorIDL([]byte(`{"name": "unnamed", "instructions": [{"name": "set", "accounts": [], "args": [{"name": "", "type": "u8"}]}]}`))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		instruction, err := idl.DecodeInstruction(nil, append(bin.SighashInstruction("set"), 7))
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]interface{}{"": uint8(7)}, instruction.Args)
+		}
+	})
+
+	t.Run("InvalidIDL", func(t *testing.T) {
+		_, err := ParseAnchorIDL([]byte(`{"accounts": [{"name": "Missing", "discriminator": [1, 2, 3, 4, 5, 6, 7, 8]}]}`))
+		assert.ErrorIs(t, err, ErrUnknownIdlType)
+		_, err = ParseAnchorIDL([]byte(`{"instructions": [{"name": "a", "args": [{"name": "x", "type": {"tuple": []}}]}]}`))
+		assert.ErrorIs(t, err, ErrUnknownIdlType)
+	})
+}
@@ -7,9 +7,39 @@ import (
 	"github.com/pvaronik/jito-go/proto"
 )
 
-// ConvertTransactionToProtobufPacket converts a solana-go Transaction to a proto.Packet.
-func ConvertTransactionToProtobufPacket(transaction types.Transaction) (proto.Packet, error) {
-	data, err := transaction.Serialize()
+// Transaction is a transaction that can be sent in a bundle. It is implemented by *solana.Transaction,
+// legacy and versioned, RawTransaction and BloctoTransaction.
+type Transaction interface {
+	MarshalBinary() ([]byte, error)
+}
+
+// RawTransaction is an already serialized transaction.
+type RawTransaction []byte
+
+func (tx RawTransaction) MarshalBinary() ([]byte, error) {
+	return tx, nil
+}
+
+// BloctoTransaction adapts a blocto/solana-go-sdk Transaction to Transaction.
+type BloctoTransaction types.Transaction
+
+func (tx BloctoTransaction) MarshalBinary() ([]byte, error) {
+	transaction := types.Transaction(tx)
+	return transaction.Serialize()
+}
+
+// Transactions converts a slice of any Transaction implementation, e.g. []*solana.Transaction, to a slice of Transaction.
+func Transactions[T Transaction](transactions []T) []Transaction {
+	txns := make([]Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		txns = append(txns, tx)
+	}
+	return txns
+}
+
+// ConvertTransactionToProtobufPacket converts a Transaction to a proto.Packet.
+func ConvertTransactionToProtobufPacket(transaction Transaction) (proto.Packet, error) {
+	data, err := transaction.MarshalBinary()
 	if err != nil {
 		return proto.Packet{}, err
 	}
@@ -26,8 +56,8 @@ func ConvertTransactionToProtobufPacket(transaction types.Transaction) (proto.Pa
 	}, nil
 }
 
-// ConvertBatchTransactionToProtobufPacket converts a slice of solana-go Transaction to a slice of proto.Packet.
-func ConvertBatchTransactionToProtobufPacket(transactions []types.Transaction) ([]*proto.Packet, error) {
+// ConvertBatchTransactionToProtobufPacket converts a slice of Transaction to a slice of proto.Packet.
+func ConvertBatchTransactionToProtobufPacket(transactions []Transaction) ([]*proto.Packet, error) {
 	packets := make([]*proto.Packet, 0, len(transactions))
 	for _, tx := range transactions {
 		packet, err := ConvertTransactionToProtobufPacket(tx)
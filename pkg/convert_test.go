@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedTransaction(t *testing.T, payer solana.PrivateKey, to solana.PublicKey, opts ...solana.TransactionOption) *solana.Transaction {
+	opts = append(opts, solana.TransactionPayer(payer.PublicKey()))
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1, payer.PublicKey(), to).Build()},
+		solana.Hash{1},
+		opts...,
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(payer.PublicKey()) {
+			return &payer
+		}
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return tx
+}
+
+func Test_ConvertTransactionToProtobufPacket(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	to := solana.NewWallet().PublicKey()
+
+	legacy := newSignedTransaction(t, payer, to)
+	versioned := newSignedTransaction(t, payer, to, solana.TransactionAddressTables(map[solana.PublicKey]solana.PublicKeySlice{
+		solana.NewWallet().PublicKey(): {to},
+	}))
+	assert.True(t, versioned.Message.IsVersioned())
+
+	raw, err := legacy.MarshalBinary()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for name, tx := range map[string]Transaction{"Legacy": legacy, "Versioned": versioned, "Raw": RawTransaction(raw)} {
+		t.Run(name, func(t *testing.T) {
+			packet, err := ConvertTransactionToProtobufPacket(tx)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, uint64(len(packet.Data)), packet.Meta.Size)
+
+			decoded, err := ConvertProtobufPacketToTransaction(&packet)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			sig, err := ExtractSigFromSerializedTx(packet.Data)
+			assert.NoError(t, err)
+			assert.Equal(t, ExtractSigFromTx(decoded), sig)
+		})
+	}
+
+	packets, err := ConvertBatchTransactionToProtobufPacket(Transactions([]*solana.Transaction{legacy, versioned}))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	sigs, err := BatchExtractSigFromPackets(packets)
+	assert.NoError(t, err)
+	assert.Equal(t, []solana.Signature{legacy.Signatures[0], versioned.Signatures[0]}, sigs)
+}
@@ -0,0 +1,290 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// Token2022ProgramID is the SPL Token-2022 program, its base instructions share the layout of the SPL Token ones.
+var Token2022ProgramID = solana.MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+
+var (
+	ErrAccountIndexOutOfRange = errors.New("instruction account index out of range")
+	ErrMissingTransaction     = errors.New("confirmed transaction holds no transaction")
+)
+
+// InstructionDecoder decodes the data of an instruction of a program, returning the instruction name
+// and its decoded form, e.g. *system.Transfer.
+type InstructionDecoder func(accounts []*solana.AccountMeta, data []byte) (name string, parsed interface{}, err error)
+
+// DecodedInstruction is an instruction of a transaction, decoded by the decoder registered for its program.
+type DecodedInstruction struct {
+	ProgramID solana.PublicKey
+	// Program is the name of the program, empty when no decoder is registered for it.
+	Program  string
+	Name     string
+	Accounts []*solana.AccountMeta
+	Data     []byte
+	// Parsed is the instruction returned by the decoder, nil when decoding failed.
+	Parsed interface{}
+	// Err is the error of the decoder, the instruction is left undecoded.
+	Err error
+	// StackHeight is 1 for the instructions of the transaction and increases with every cross program invocation.
+	StackHeight int
+	// Inner are the instructions invoked by the instruction, in execution order, when the transaction meta is known.
+	Inner []*DecodedInstruction
+}
+
+type registeredDecoder struct {
+	program string
+	decode  InstructionDecoder
+}
+
+// InstructionRegistry decodes transactions with the decoders registered by program ID.
+type InstructionRegistry struct {
+	mu       sync.RWMutex
+	decoders map[solana.PublicKey]registeredDecoder
+}
+
+// NewInstructionRegistry creates an InstructionRegistry decoding the System, SPL Token, Token-2022,
+// Associated Token Account, ComputeBudget and Memo programs.
+func NewInstructionRegistry() *InstructionRegistry {
+	r := &InstructionRegistry{decoders: make(map[solana.PublicKey]registeredDecoder)}
+
+	r.Register(solana.SystemProgramID, system.ProgramName, decodeSystemInstruction)
+	r.Register(solana.TokenProgramID, token.ProgramName, decodeTokenInstruction)
+	r.Register(Token2022ProgramID, "Token2022", decodeTokenInstruction)
+	r.Register(solana.SPLAssociatedTokenAccountProgramID, "AssociatedTokenAccount", decodeAssociatedTokenInstruction)
+	r.Register(solana.ComputeBudget, computebudget.ProgramName, decodeComputeBudgetInstruction)
+	r.Register(solana.MemoProgramID, "Memo", decodeMemoInstruction)
+
+	return r
+}
+
+// Register decodes the instructions of programID with decoder, replacing the decoder registered before.
+func (r *InstructionRegistry) Register(programID solana.PublicKey, program string, decoder InstructionDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decoders[programID] = registeredDecoder{program: program, decode: decoder}
+}
+
+// DecodeInstruction decodes an instruction of programID, it is left undecoded when no decoder is registered.
+func (r *InstructionRegistry) DecodeInstruction(programID solana.PublicKey, accounts []*solana.AccountMeta, data []byte) *DecodedInstruction {
+	decoded := &DecodedInstruction{ProgramID: programID, Accounts: accounts, Data: data, StackHeight: 1}
+
+	r.mu.RLock()
+	decoder, ok := r.decoders[programID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return decoded
+	}
+
+	decoded.Program = decoder.program
+	decoded.Name, decoded.Parsed, decoded.Err = decoder.decode(accounts, data)
+	return decoded
+}
+
+// DecodeTransaction decodes the instructions of tx, e.g. from the mempool or packet streams.
+// keys are the account keys loaded by tx, see LookupTableResolver. When nil, the static keys of the message are used,
+// which is enough for legacy transactions and versioned ones without lookups.
+func (r *InstructionRegistry) DecodeTransaction(tx *solana.Transaction, keys []AccountKey) ([]*DecodedInstruction, error) {
+	if keys == nil {
+		var err error
+		if keys, err = staticAccountKeys(&tx.Message); err != nil {
+			return nil, err
+		}
+	}
+
+	instructions := make([]*DecodedInstruction, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		accounts := make([]uint32, len(instruction.Accounts))
+		for i, index := range instruction.Accounts {
+			accounts[i] = uint32(index)
+		}
+
+		decoded, err := r.decodeCompiled(keys, uint32(instruction.ProgramIDIndex), accounts, instruction.Data)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, decoded)
+	}
+
+	return instructions, nil
+}
+
+// DecodeConfirmedTransaction decodes the instructions of a transaction streamed by geyser, see OnTransactionUpdates.
+// The inner instructions are decoded when the meta holds them.
+func (r *InstructionRegistry) DecodeConfirmedTransaction(confirmed *proto.ConfirmedTransaction) ([]*DecodedInstruction, error) {
+	msg := confirmed.GetTransaction().GetMessage()
+	if msg == nil {
+		return nil, ErrMissingTransaction
+	}
+	meta := confirmed.GetMeta()
+
+	keys := confirmedAccountKeys(msg, meta)
+
+	instructions := make([]*DecodedInstruction, 0, len(msg.GetInstructions()))
+	for _, instruction := range msg.GetInstructions() {
+		decoded, err := r.decodeCompiled(keys, instruction.GetProgramIdIndex(), bytesToIndexes(instruction.GetAccounts()), instruction.GetData())
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, decoded)
+	}
+
+	for _, inner := range meta.GetInnerInstructions() {
+		if int(inner.GetIndex()) >= len(instructions) {
+			return nil, fmt.Errorf("%w: inner instructions of instruction %d", ErrAccountIndexOutOfRange, inner.GetIndex())
+		}
+		parent := instructions[inner.GetIndex()]
+
+		for _, instruction := range inner.GetInstructions() {
+			decoded, err := r.decodeCompiled(keys, instruction.GetProgramIdIndex(), bytesToIndexes(instruction.GetAccounts()), instruction.GetData())
+			if err != nil {
+				return nil, err
+			}
+
+			// the stack height is unknown before Solana v1.14.6
+			decoded.StackHeight = 2
+			if instruction.StackHeight != nil {
+				decoded.StackHeight = int(instruction.GetStackHeight())
+			}
+			parent.Inner = append(parent.Inner, decoded)
+		}
+	}
+
+	return instructions, nil
+}
+
+func (r *InstructionRegistry) decodeCompiled(keys []AccountKey, programIndex uint32, accountIndexes []uint32, data []byte) (*DecodedInstruction, error) {
+	if int(programIndex) >= len(keys) {
+		return nil, fmt.Errorf("%w: program index %d", ErrAccountIndexOutOfRange, programIndex)
+	}
+
+	accounts := make([]*solana.AccountMeta, len(accountIndexes))
+	for i, index := range accountIndexes {
+		if int(index) >= len(keys) {
+			return nil, fmt.Errorf("%w: account index %d", ErrAccountIndexOutOfRange, index)
+		}
+		key := keys[index]
+		accounts[i] = &solana.AccountMeta{PublicKey: key.PublicKey, IsWritable: key.Writable, IsSigner: key.Signer}
+	}
+
+	return r.DecodeInstruction(keys[programIndex].PublicKey, accounts, data), nil
+}
+
+// confirmedAccountKeys returns the static keys of msg followed by the addresses loaded from lookup tables.
+func confirmedAccountKeys(msg *proto.Message, meta *proto.TransactionStatusMeta) []AccountKey {
+	header := msg.GetHeader()
+	signers := int(header.GetNumRequiredSignatures())
+	staticKeys := len(msg.GetAccountKeys())
+
+	keys := make([]AccountKey, 0, staticKeys+len(meta.GetLoadedWritableAddresses())+len(meta.GetLoadedReadonlyAddresses()))
+	for i, key := range msg.GetAccountKeys() {
+		signer := i < signers
+		var writable bool
+		if signer {
+			writable = i < signers-int(header.GetNumReadonlySignedAccounts())
+		} else {
+			writable = i < staticKeys-int(header.GetNumReadonlyUnsignedAccounts())
+		}
+		keys = append(keys, AccountKey{PublicKey: solana.PublicKeyFromBytes(key), Signer: signer, Writable: writable})
+	}
+
+	for _, key := range meta.GetLoadedWritableAddresses() {
+		keys = append(keys, AccountKey{PublicKey: solana.PublicKeyFromBytes(key), Writable: true})
+	}
+	for _, key := range meta.GetLoadedReadonlyAddresses() {
+		keys = append(keys, AccountKey{PublicKey: solana.PublicKeyFromBytes(key)})
+	}
+
+	return keys
+}
+
+func bytesToIndexes(b []byte) []uint32 {
+	indexes := make([]uint32, len(b))
+	for i, index := range b {
+		indexes[i] = uint32(index)
+	}
+	return indexes
+}
+
+func decodeSystemInstruction(accounts []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+	instruction, err := system.DecodeInstruction(accounts, data)
+	if err != nil {
+		return "", nil, err
+	}
+	return system.InstructionIDToName(instruction.TypeID.Uint32()), instruction.Impl, nil
+}
+
+func decodeTokenInstruction(accounts []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+	instruction, err := token.DecodeInstruction(accounts, data)
+	if err != nil {
+		return "", nil, err
+	}
+	return token.InstructionIDToName(instruction.TypeID.Uint8()), instruction.Impl, nil
+}
+
+func decodeComputeBudgetInstruction(accounts []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+	instruction, err := computebudget.DecodeInstruction(accounts, data)
+	if err != nil {
+		return "", nil, err
+	}
+	return computebudget.InstructionIDToName(instruction.TypeID.Uint8()), instruction.Impl, nil
+}
+
+// AssociatedTokenInstruction is a decoded Create or CreateIdempotent instruction of the Associated Token Account program.
+type AssociatedTokenInstruction struct {
+	Payer   solana.PublicKey
+	Account solana.PublicKey
+	Wallet  solana.PublicKey
+	Mint    solana.PublicKey
+}
+
+// decodeAssociatedTokenInstruction decodes the Associated Token Account instructions, which have no data
+// but a one byte discriminator since CreateIdempotent, decoding RecoverNested to nil.
+func decodeAssociatedTokenInstruction(accounts []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+	var discriminator byte
+	if len(data) != 0 {
+		discriminator = data[0]
+	}
+
+	var name string
+	switch discriminator {
+	case 0:
+		name = "Create"
+	case 1:
+		name = "CreateIdempotent"
+	case 2:
+		return "RecoverNested", nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown associated token account instruction %d", discriminator)
+	}
+
+	if len(accounts) < 4 {
+		return name, nil, fmt.Errorf("%s: %w", name, ErrAccountIndexOutOfRange)
+	}
+	return name, &AssociatedTokenInstruction{
+		Payer:   accounts[0].PublicKey,
+		Account: accounts[1].PublicKey,
+		Wallet:  accounts[2].PublicKey,
+		Mint:    accounts[3].PublicKey,
+	}, nil
+}
+
+// decodeMemoInstruction decodes the memo to a string.
+func decodeMemoInstruction(_ []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+	if !utf8.Valid(data) {
+		return "Memo", nil, errors.New("memo is not valid UTF-8")
+	}
+	return "Memo", string(data), nil
+}
@@ -0,0 +1,158 @@
+package pkg
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InstructionRegistry(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	to := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	custom := solana.NewWallet().PublicKey()
+
+	tx, err := solana.NewTransaction([]solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(200_000).Build(),
+		system.NewTransferInstruction(5, payer, to).Build(),
+		solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{}, []byte("gm")),
+		token.NewTransferInstruction(7, to, to, payer, nil).Build(),
+		solana.NewInstruction(solana.SPLAssociatedTokenAccountProgramID, solana.AccountMetaSlice{
+			solana.Meta(payer).WRITE().SIGNER(),
+			solana.Meta(to).WRITE(),
+			solana.Meta(payer),
+			solana.Meta(mint),
+			solana.Meta(solana.SystemProgramID),
+			solana.Meta(solana.TokenProgramID),
+		}, []byte{1}),
+		solana.NewInstruction(custom, solana.AccountMetaSlice{solana.Meta(payer).WRITE().SIGNER()}, []byte{1, 0, 0, 0}),
+	}, solana.Hash{1}, solana.TransactionPayer(payer))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Builtins", func(t *testing.T) {
+		instructions, err := NewInstructionRegistry().DecodeTransaction(tx, nil)
+		if !assert.NoError(t, err) || !assert.Len(t, instructions, 6) {
+			t.FailNow()
+		}
+
+		for _, instruction := range instructions {
+			assert.NoError(t, instruction.Err)
+			assert.Equal(t, 1, instruction.StackHeight)
+		}
+
+		assert.Equal(t, computebudget.ProgramName, instructions[0].Program)
+		assert.Equal(t, "SetComputeUnitLimit", instructions[0].Name)
+		if limit, ok := instructions[0].Parsed.(*computebudget.SetComputeUnitLimit); assert.True(t, ok) {
+			assert.Equal(t, uint32(200_000), limit.Units)
+		}
+
+		assert.Equal(t, "Transfer", instructions[1].Name)
+		if transfer, ok := instructions[1].Parsed.(*system.Transfer); assert.True(t, ok) {
+			assert.Equal(t, uint64(5), *transfer.Lamports)
+			assert.Equal(t, to, transfer.GetRecipientAccount().PublicKey)
+		}
+		assert.True(t, instructions[1].Accounts[0].IsSigner)
+		assert.True(t, instructions[1].Accounts[1].IsWritable)
+
+		assert.Equal(t, "gm", instructions[2].Parsed)
+
+		assert.Equal(t, token.ProgramName, instructions[3].Program)
+		if transfer, ok := instructions[3].Parsed.(*token.Transfer); assert.True(t, ok) {
+			assert.Equal(t, uint64(7), *transfer.Amount)
+		}
+
+		assert.Equal(t, "CreateIdempotent", instructions[4].Name)
+		assert.Equal(t, &AssociatedTokenInstruction{Payer: payer, Account: to, Wallet: payer, Mint: mint}, instructions[4].Parsed)
+
+		assert.Equal(t, custom, instructions[5].ProgramID)
+		assert.Empty(t, instructions[5].Program)
+		assert.Nil(t, instructions[5].Parsed)
+		assert.Equal(t, []byte{1, 0, 0, 0}, instructions[5].Data)
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		registry := NewInstructionRegistry()
+		registry.Register(custom, "Counter", func(accounts []*solana.AccountMeta, data []byte) (string, interface{}, error) {
+			return "Increment", binary.LittleEndian.Uint32(data), nil
+		})
+
+		instructions, err := registry.DecodeTransaction(tx, nil)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, "Counter", instructions[5].Program)
+		assert.Equal(t, "Increment", instructions[5].Name)
+		assert.Equal(t, uint32(1), instructions[5].Parsed)
+
+		bad := registry.DecodeInstruction(solana.SystemProgramID, nil, []byte{0xff})
+		assert.Error(t, bad.Err)
+		assert.Nil(t, bad.Parsed)
+	})
+
+	t.Run("ConfirmedTransaction", func(t *testing.T) {
+		loaded := solana.NewWallet().PublicKey()
+
+		msg := &proto.Message{
+			Header: &proto.MessageHeader{
+				NumRequiredSignatures:       uint32(tx.Message.Header.NumRequiredSignatures),
+				NumReadonlySignedAccounts:   uint32(tx.Message.Header.NumReadonlySignedAccounts),
+				NumReadonlyUnsignedAccounts: uint32(tx.Message.Header.NumReadonlyUnsignedAccounts),
+			},
+			Versioned: true,
+		}
+		for _, key := range tx.Message.AccountKeys {
+			msg.AccountKeys = append(msg.AccountKeys, key.Bytes())
+		}
+		customIndex := tx.Message.Instructions[5].ProgramIDIndex
+		msg.Instructions = []*proto.CompiledInstruction{{ProgramIdIndex: uint32(customIndex), Accounts: []byte{0}, Data: []byte{2}}}
+
+		systemIndex := byte(len(msg.AccountKeys) + 1)
+		msg.AccountKeys = append(msg.AccountKeys, solana.SystemProgramID.Bytes())
+		msg.Header.NumReadonlyUnsignedAccounts++
+
+		stackHeight := uint32(3)
+		transfer, err := system.NewTransferInstruction(9, payer, loaded).Build().Data()
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		confirmed := &proto.ConfirmedTransaction{
+			Transaction: &proto.Transaction{Message: msg},
+			Meta: &proto.TransactionStatusMeta{
+				LoadedWritableAddresses: [][]byte{loaded.Bytes()},
+				InnerInstructions: []*proto.InnerInstructions{{Index: 0, Instructions: []*proto.InnerInstruction{
+					{ProgramIdIndex: uint32(systemIndex - 1), Accounts: []byte{0, systemIndex}, Data: transfer},
+					{ProgramIdIndex: uint32(systemIndex - 1), Accounts: []byte{0, systemIndex}, Data: transfer, StackHeight: &stackHeight},
+				}}},
+			},
+		}
+
+		instructions, err := NewInstructionRegistry().DecodeConfirmedTransaction(confirmed)
+		if !assert.NoError(t, err) || !assert.Len(t, instructions, 1) || !assert.Len(t, instructions[0].Inner, 2) {
+			t.FailNow()
+		}
+		assert.Equal(t, custom, instructions[0].ProgramID)
+
+		inner := instructions[0].Inner
+		assert.Equal(t, 2, inner[0].StackHeight)
+		assert.Equal(t, 3, inner[1].StackHeight)
+		assert.Equal(t, "Transfer", inner[0].Name)
+		assert.Equal(t, loaded, inner[0].Accounts[1].PublicKey)
+		assert.True(t, inner[0].Accounts[1].IsWritable)
+
+		confirmed.Meta.LoadedWritableAddresses = nil
+		_, err = NewInstructionRegistry().DecodeConfirmedTransaction(confirmed)
+		assert.ErrorIs(t, err, ErrAccountIndexOutOfRange)
+
+		_, err = NewInstructionRegistry().DecodeConfirmedTransaction(&proto.ConfirmedTransaction{})
+		assert.ErrorIs(t, err, ErrMissingTransaction)
+	})
+}
@@ -0,0 +1,239 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	lookup "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/pvaronik/jito-go/proto"
+)
+
+// AddressLookupTableProgramID owns the address lookup tables.
+var AddressLookupTableProgramID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+var (
+	ErrLookupTableNotFound   = errors.New("address lookup table not found")
+	ErrLookupIndexOutOfRange = errors.New("address lookup table index out of range")
+)
+
+// AccountKey is an account loaded by a transaction, in the order of the runtime:
+// the static keys of the message, then the writable and the readonly keys of every lookup.
+type AccountKey struct {
+	PublicKey solana.PublicKey
+	Signer    bool
+	Writable  bool
+	// Table is the lookup table the key was loaded from, nil for the static keys.
+	Table *solana.PublicKey
+}
+
+// LookupTableFetcher returns the addresses of an address lookup table.
+type LookupTableFetcher func(ctx context.Context, table solana.PublicKey) (solana.PublicKeySlice, error)
+
+// RpcLookupTableFetcher fetches the address lookup tables with getAccountInfo.
+func RpcLookupTableFetcher(client *rpc.Client) LookupTableFetcher {
+	return func(ctx context.Context, table solana.PublicKey) (solana.PublicKeySlice, error) {
+		account, err := client.GetAccountInfo(ctx, table)
+		if err != nil {
+			if errors.Is(err, rpc.ErrNotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrLookupTableNotFound, table)
+			}
+			return nil, err
+		}
+
+		state, err := lookup.DecodeAddressLookupTableState(account.GetBinary())
+		if err != nil {
+			return nil, err
+		}
+		return state.Addresses, nil
+	}
+}
+
+// LookupTableResolver resolves the accounts loaded by the address lookup tables of versioned transactions.
+// Tables are cached in memory: they are append only, so a cached table is only fetched again when a lookup
+// index is past its end. Update keeps the cache fresh from account updates, e.g. the geyser program updates.
+type LookupTableResolver struct {
+	fetch LookupTableFetcher
+
+	mu     sync.RWMutex
+	tables map[solana.PublicKey]solana.PublicKeySlice
+}
+
+// NewLookupTableResolver creates a LookupTableResolver fetching the missing tables with fetch.
+func NewLookupTableResolver(fetch LookupTableFetcher) *LookupTableResolver {
+	return &LookupTableResolver{
+		fetch:  fetch,
+		tables: make(map[solana.PublicKey]solana.PublicKeySlice),
+	}
+}
+
+// Resolve returns every account key loaded by tx with its signer and writable flags.
+func (r *LookupTableResolver) Resolve(ctx context.Context, tx *solana.Transaction) ([]AccountKey, error) {
+	msg := &tx.Message
+	keys, err := staticAccountKeys(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !msg.IsVersioned() {
+		return keys, nil
+	}
+
+	lookups := msg.GetAddressTableLookups()
+	var readonly []AccountKey
+	for _, l := range lookups {
+		table := l.AccountKey
+
+		addresses, err := r.table(ctx, table, l)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, idx := range l.WritableIndexes {
+			keys = append(keys, AccountKey{PublicKey: addresses[idx], Writable: true, Table: &table})
+		}
+		for _, idx := range l.ReadonlyIndexes {
+			readonly = append(readonly, AccountKey{PublicKey: addresses[idx], Table: &table})
+		}
+	}
+
+	return append(keys, readonly...), nil
+}
+
+// staticAccountKeys returns the keys of msg with their flags, without the ones loaded from lookup tables.
+// msg is round-tripped through its wire format, as the AccountKeys of messages whose lookups were resolved by
+// solana-go hold the loaded keys too.
+func staticAccountKeys(msg *solana.Message) ([]AccountKey, error) {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	static := new(solana.Message)
+	if err = static.UnmarshalWithDecoder(bin.NewBinDecoder(data)); err != nil {
+		return nil, err
+	}
+
+	header := static.Header
+	staticKeys := len(static.AccountKeys)
+
+	keys := make([]AccountKey, 0, staticKeys+msg.NumLookups())
+	for i, key := range static.AccountKeys {
+		signer := i < int(header.NumRequiredSignatures)
+		var writable bool
+		if signer {
+			writable = i < int(header.NumRequiredSignatures)-int(header.NumReadonlySignedAccounts)
+		} else {
+			writable = i < staticKeys-int(header.NumReadonlyUnsignedAccounts)
+		}
+		keys = append(keys, AccountKey{PublicKey: key, Signer: signer, Writable: writable})
+	}
+
+	return keys, nil
+}
+
+// ResolvePacket decodes packet, from the mempool, relayer or validator streams, and resolves its account keys.
+func (r *LookupTableResolver) ResolvePacket(ctx context.Context, packet *proto.Packet) (*solana.Transaction, []AccountKey, error) {
+	tx, err := ConvertProtobufPacketToTransaction(packet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys, err := r.Resolve(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, keys, nil
+}
+
+// Update caches the addresses of table, replacing the cached ones.
+func (r *LookupTableResolver) Update(table solana.PublicKey, addresses solana.PublicKeySlice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tables[table] = addresses
+}
+
+// UpdateAccount caches the lookup table held by update, the updates of other accounts are ignored.
+func (r *LookupTableResolver) UpdateAccount(update *proto.AccountUpdate) error {
+	if !solana.PublicKeyFromBytes(update.GetOwner()).Equals(AddressLookupTableProgramID) {
+		return nil
+	}
+
+	table := solana.PublicKeyFromBytes(update.GetPubkey())
+	if len(update.GetData()) == 0 {
+		r.Invalidate(table)
+		return nil
+	}
+
+	state, err := lookup.DecodeAddressLookupTableState(update.GetData())
+	if err != nil {
+		return fmt.Errorf("failed to decode lookup table %s: %w", table, err)
+	}
+
+	r.Update(table, state.Addresses)
+	return nil
+}
+
+// Watch applies the account updates received from updates with UpdateAccount until ctx is done or updates is closed.
+func (r *LookupTableResolver) Watch(ctx context.Context, updates <-chan *proto.AccountUpdate) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			// undecodable tables are refetched when a transaction loads them
+			if err := r.UpdateAccount(update); err != nil {
+				r.Invalidate(solana.PublicKeyFromBytes(update.GetPubkey()))
+			}
+		}
+	}
+}
+
+// Invalidate removes table from the cache.
+func (r *LookupTableResolver) Invalidate(table solana.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tables, table)
+}
+
+// table returns the addresses of table, fetching them when missing from the cache or too short for l.
+func (r *LookupTableResolver) table(ctx context.Context, table solana.PublicKey, l solana.MessageAddressTableLookup) (solana.PublicKeySlice, error) {
+	r.mu.RLock()
+	addresses, ok := r.tables[table]
+	r.mu.RUnlock()
+
+	if ok && covers(addresses, l) {
+		return addresses, nil
+	}
+
+	addresses, err := r.fetch(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	r.Update(table, addresses)
+
+	if !covers(addresses, l) {
+		return nil, fmt.Errorf("%w: table %s holds %d addresses", ErrLookupIndexOutOfRange, table, len(addresses))
+	}
+	return addresses, nil
+}
+
+// covers reports whether every index of l is in addresses.
+func covers(addresses solana.PublicKeySlice, l solana.MessageAddressTableLookup) bool {
+	for _, indexes := range [][]uint8{l.WritableIndexes, l.ReadonlyIndexes} {
+		for _, idx := range indexes {
+			if int(idx) >= len(addresses) {
+				return false
+			}
+		}
+	}
+	return true
+}
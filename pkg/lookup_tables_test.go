@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	lookup "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeLookupTable(t *testing.T, addresses solana.PublicKeySlice) []byte {
+	var buf bytes.Buffer
+	state := lookup.AddressLookupTableState{TypeIndex: 1, DeactivationSlot: math.MaxUint64, Addresses: addresses}
+	if !assert.NoError(t, state.MarshalWithEncoder(bin.NewBinEncoder(&buf))) {
+		t.FailNow()
+	}
+	return buf.Bytes()
+}
+
+func Test_LookupTableResolver(t *testing.T) {
+	ctx := context.Background()
+
+	payer := solana.NewWallet().PrivateKey
+	program := solana.NewWallet().PublicKey()
+	writable := solana.NewWallet().PublicKey()
+	readonly := solana.NewWallet().PublicKey()
+	table := solana.NewWallet().PublicKey()
+	tables := map[solana.PublicKey]solana.PublicKeySlice{table: {readonly, writable}}
+
+	instruction := solana.NewInstruction(program, solana.AccountMetaSlice{
+		solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+		solana.Meta(writable).WRITE(),
+		solana.Meta(readonly),
+	}, []byte{1})
+	tx, err := solana.NewTransaction([]solana.Instruction{instruction}, solana.Hash{1}, solana.TransactionPayer(payer.PublicKey()), solana.TransactionAddressTables(tables))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if _, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer }); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	packet, err := ConvertTransactionToProtobufPacket(tx)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	expected := []AccountKey{
+		{PublicKey: payer.PublicKey(), Signer: true, Writable: true},
+		{PublicKey: program},
+		{PublicKey: writable, Writable: true, Table: &table},
+		{PublicKey: readonly, Table: &table},
+	}
+
+	var fetches atomic.Int64
+	fetched := tables[table]
+	resolver := NewLookupTableResolver(func(_ context.Context, key solana.PublicKey) (solana.PublicKeySlice, error) {
+		fetches.Add(1)
+		if !key.Equals(table) {
+			return nil, ErrLookupTableNotFound
+		}
+		return fetched, nil
+	})
+
+	t.Run("ResolvePacket", func(t *testing.T) {
+		decoded, keys, err := resolver.ResolvePacket(ctx, &packet)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, tx.Signatures, decoded.Signatures)
+		assert.Equal(t, expected, keys)
+		assert.Equal(t, int64(1), fetches.Load())
+
+		_, _, err = resolver.ResolvePacket(ctx, &packet)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), fetches.Load(), "the table is cached")
+	})
+
+	t.Run("Legacy", func(t *testing.T) {
+		legacy := newSignedTransaction(t, payer, writable)
+		keys, err := resolver.Resolve(ctx, legacy)
+		assert.NoError(t, err)
+		assert.Equal(t, []AccountKey{
+			{PublicKey: payer.PublicKey(), Signer: true, Writable: true},
+			{PublicKey: writable, Writable: true},
+			{PublicKey: solana.SystemProgramID},
+		}, keys)
+	})
+
+	t.Run("ResolvedMessage", func(t *testing.T) {
+		resolved, err := ConvertProtobufPacketToTransaction(&packet)
+		if !assert.NoError(t, err) || !assert.NoError(t, resolved.Message.SetAddressTables(tables)) || !assert.NoError(t, resolved.Message.ResolveLookups()) {
+			t.FailNow()
+		}
+		assert.Len(t, resolved.Message.AccountKeys, len(expected), "the loaded keys are appended to the message keys")
+
+		keys, err := resolver.Resolve(ctx, resolved)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, keys)
+	})
+
+	t.Run("RefetchesExtendedTable", func(t *testing.T) {
+		fetches.Store(0)
+		resolver.Update(table, tables[table][:1])
+
+		keys, err := resolver.Resolve(ctx, tx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, keys)
+		assert.Equal(t, int64(1), fetches.Load())
+
+		fetched = tables[table][:1]
+		resolver.Invalidate(table)
+		_, err = resolver.Resolve(ctx, tx)
+		assert.ErrorIs(t, err, ErrLookupIndexOutOfRange)
+		fetched = tables[table]
+	})
+
+	t.Run("UpdateAccount", func(t *testing.T) {
+		geyser := NewLookupTableResolver(func(context.Context, solana.PublicKey) (solana.PublicKeySlice, error) {
+			return nil, ErrLookupTableNotFound
+		})
+
+		_, err := geyser.Resolve(ctx, tx)
+		assert.ErrorIs(t, err, ErrLookupTableNotFound)
+
+		updates := make(chan *proto.AccountUpdate, 2)
+		updates <- &proto.AccountUpdate{Pubkey: program.Bytes(), Owner: solana.SystemProgramID.Bytes(), Data: []byte{1}}
+		updates <- &proto.AccountUpdate{Pubkey: table.Bytes(), Owner: AddressLookupTableProgramID.Bytes(), Data: encodeLookupTable(t, tables[table])}
+		close(updates)
+		geyser.Watch(ctx, updates)
+
+		keys, err := geyser.Resolve(ctx, tx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, keys)
+
+		assert.Error(t, geyser.UpdateAccount(&proto.AccountUpdate{Pubkey: table.Bytes(), Owner: AddressLookupTableProgramID.Bytes(), Data: []byte{1}}))
+	})
+}
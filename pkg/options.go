@@ -0,0 +1,276 @@
+package pkg
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor for WithCompression
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metrics receives the outcome of the RPCs of a client, e.g. to export them to Prometheus.
+type Metrics interface {
+	// ObserveRPC is called when a unary call returns, or when a stream is opened.
+	ObserveRPC(method string, duration time.Duration, err error)
+}
+
+// ClientOptions configure the gRPC clients, they are set with ClientOption functions.
+type ClientOptions struct {
+	// Ctx bounds the construction and the background goroutines of the client, defaults to context.Background().
+	Ctx context.Context
+	// Timeout bounds the construction of the client, e.g. the authentication, no timeout when zero.
+	Timeout time.Duration
+	// CallTimeout is the deadline of the unary calls whose context has none, no timeout when zero.
+	CallTimeout time.Duration
+	// TLSConfig defaults to the system roots, ignored when Insecure.
+	TLSConfig *tls.Config
+	// Insecure connects without TLS, e.g. to local endpoints.
+	Insecure    bool
+	DialOptions []grpc.DialOption
+	Keepalive   *keepalive.ClientParameters
+	// MaxRecvMsgSize and MaxSendMsgSize bound the size of the messages, gRPC defaults when zero.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// Compression is the name of the compressor of the calls, e.g. "gzip".
+	Compression string
+	// LazyAuth authenticates on the first call instead of during the construction.
+	LazyAuth bool
+	Logger   *slog.Logger
+	Metrics  Metrics
+}
+
+// ClientOption sets a ClientOptions field.
+type ClientOption func(*ClientOptions)
+
+// NewClientOptions applies opts over the defaults.
+func NewClientOptions(opts ...ClientOption) *ClientOptions {
+	o := &ClientOptions{Ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Ctx == nil {
+		o.Ctx = context.Background()
+	}
+	return o
+}
+
+// WithContext bounds the construction and the background goroutines of the client to ctx.
+func WithContext(ctx context.Context) ClientOption {
+	return func(o *ClientOptions) { o.Ctx = ctx }
+}
+
+// WithTimeout bounds the construction of the client.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *ClientOptions) { o.Timeout = timeout }
+}
+
+// WithCallTimeout sets the deadline of the unary calls whose context has none.
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(o *ClientOptions) { o.CallTimeout = timeout }
+}
+
+// WithTLSConfig connects with TLS configured by config, nil uses the system roots.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(o *ClientOptions) {
+		o.TLSConfig = config
+		o.Insecure = false
+	}
+}
+
+// WithInsecure connects without TLS, e.g. to local endpoints.
+func WithInsecure() ClientOption {
+	return func(o *ClientOptions) { o.Insecure = true }
+}
+
+// WithDialOptions appends gRPC dial options, applied after the ones derived from the other options.
+func WithDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *ClientOptions) { o.DialOptions = append(o.DialOptions, opts...) }
+}
+
+// WithKeepalive pings the server to keep the connection alive, see keepalive.ClientParameters.
+func WithKeepalive(params keepalive.ClientParameters) ClientOption {
+	return func(o *ClientOptions) { o.Keepalive = &params }
+}
+
+// WithMaxMsgSize bounds the size of the received and sent messages.
+func WithMaxMsgSize(recv, send int) ClientOption {
+	return func(o *ClientOptions) {
+		o.MaxRecvMsgSize = recv
+		o.MaxSendMsgSize = send
+	}
+}
+
+// WithCompression compresses the calls with the compressor registered as name, e.g. "gzip".
+func WithCompression(name string) ClientOption {
+	return func(o *ClientOptions) { o.Compression = name }
+}
+
+// WithLazyAuth authenticates on the first call instead of during the construction.
+func WithLazyAuth() ClientOption {
+	return func(o *ClientOptions) { o.LazyAuth = true }
+}
+
+// WithLogger logs the failed calls at debug level and the access token refresh failures.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(o *ClientOptions) { o.Logger = logger }
+}
+
+// WithMetrics reports the outcome of every call to metrics.
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(o *ClientOptions) { o.Metrics = metrics }
+}
+
+// ConstructionContext returns the context bounding the construction of the client.
+func (o *ClientOptions) ConstructionContext() (context.Context, context.CancelFunc) {
+	if o.Timeout > 0 {
+		return context.WithTimeout(o.Ctx, o.Timeout)
+	}
+	return context.WithCancel(o.Ctx)
+}
+
+// TransportCredentials returns the credentials of the connections, insecure or TLS configured by TLSConfig.
+func (o *ClientOptions) TransportCredentials() credentials.TransportCredentials {
+	if o.Insecure {
+		return insecure.NewCredentials()
+	}
+	if o.TLSConfig != nil {
+		return credentials.NewTLS(o.TLSConfig)
+	}
+	return credentials.NewTLS(&tls.Config{})
+}
+
+// Dial creates an observed connection to target, see CreateAndObserveGRPCConn.
+// When auth is not nil, the calls carry its access token and authenticate it with role first under LazyAuth.
+func (o *ClientOptions) Dial(target string, auth *AuthenticationService, role proto.Role) (*grpc.ClientConn, error) {
+	opts := make([]grpc.DialOption, 0, len(o.DialOptions)+4)
+	opts = append(opts, grpc.WithTransportCredentials(o.TransportCredentials()))
+
+	if o.Keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(*o.Keepalive))
+	}
+
+	var callOpts []grpc.CallOption
+	if o.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(o.MaxRecvMsgSize))
+	}
+	if o.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(o.MaxSendMsgSize))
+	}
+	if o.Compression != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(o.Compression))
+	}
+	if len(callOpts) != 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	interceptors := &clientInterceptors{options: o, auth: auth, role: role}
+	opts = append(opts, grpc.WithChainUnaryInterceptor(interceptors.unary), grpc.WithChainStreamInterceptor(interceptors.stream))
+
+	return CreateAndObserveGRPCConn(o.Ctx, target, append(opts, o.DialOptions...)...)
+}
+
+// NewAuthenticationService dials target and creates the AuthenticationService of the connection,
+// authenticated with role unless LazyAuth.
+func (o *ClientOptions) NewAuthenticationService(target string, privateKey solana.PrivateKey, role proto.Role) (*grpc.ClientConn, *AuthenticationService, error) {
+	auth := NewAuthenticationServiceContext(o.Ctx, nil, privateKey)
+	auth.Logger = o.Logger
+
+	conn, err := o.Dial(target, auth, role)
+	if err != nil {
+		return nil, nil, err
+	}
+	auth.AuthService = proto.NewAuthServiceClient(conn)
+
+	if o.LazyAuth {
+		return conn, auth, nil
+	}
+
+	ctx, cancel := o.ConstructionContext()
+	defer cancel()
+
+	if err = auth.Authenticate(ctx, role); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, auth, nil
+}
+
+// clientInterceptors implement the call timeout, the lazy authentication, the logger and the metrics.
+type clientInterceptors struct {
+	options *ClientOptions
+	auth    *AuthenticationService
+	role    proto.Role
+}
+
+func (i *clientInterceptors) unary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if _, ok := ctx.Deadline(); !ok && i.options.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.options.CallTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	ctx, err := i.authorize(ctx, method)
+	if err == nil {
+		err = invoker(ctx, method, req, reply, cc, opts...)
+	}
+	i.observe(method, start, err)
+
+	return err
+}
+
+func (i *clientInterceptors) stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	ctx, err := i.authorize(ctx, method)
+
+	var stream grpc.ClientStream
+	if err == nil {
+		stream, err = streamer(ctx, desc, cc, method, opts...)
+	}
+	i.observe(method, start, err)
+
+	return stream, err
+}
+
+// authorize authenticates under LazyAuth and adds the access token to ctx when missing.
+// The calls of the auth service itself are left as is.
+func (i *clientInterceptors) authorize(ctx context.Context, method string) (context.Context, error) {
+	if i.auth == nil || strings.HasPrefix(method, "/auth.AuthService/") {
+		return ctx, nil
+	}
+
+	if i.options.LazyAuth {
+		if err := i.auth.EnsureAuthenticated(ctx, i.role); err != nil {
+			return ctx, err
+		}
+	}
+
+	if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get("authorization")) != 0 {
+		return ctx, nil
+	}
+
+	token := i.auth.BearerTokenValue()
+	if token == "" {
+		return ctx, nil
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), nil
+}
+
+func (i *clientInterceptors) observe(method string, start time.Time, err error) {
+	if i.options.Metrics != nil {
+		i.options.Metrics.ObserveRPC(method, time.Since(start), err)
+	}
+	if err != nil && i.options.Logger != nil {
+		i.options.Logger.Debug("rpc failed", "method", method, "err", err)
+	}
+}
@@ -0,0 +1,204 @@
+package pkg
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type fakeAuthServer struct {
+	proto.UnimplementedAuthServiceServer
+	proto.UnimplementedRelayerServer
+
+	mu            sync.Mutex
+	challenges    int
+	refreshes     int
+	authorization []string
+	block         chan struct{}
+	// accessTTL is the lifetime of the generated access tokens, an hour when zero
+	accessTTL  time.Duration
+	refreshErr error
+}
+
+func (s *fakeAuthServer) GenerateAuthChallenge(context.Context, *proto.GenerateAuthChallengeRequest) (*proto.GenerateAuthChallengeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges++
+	return &proto.GenerateAuthChallengeResponse{Challenge: "challenge"}, nil
+}
+
+func (s *fakeAuthServer) GenerateAuthTokens(context.Context, *proto.GenerateAuthTokensRequest) (*proto.GenerateAuthTokensResponse, error) {
+	s.mu.Lock()
+	ttl := s.accessTTL
+	s.mu.Unlock()
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+
+	return &proto.GenerateAuthTokensResponse{
+		AccessToken:  &proto.Token{Value: "access", ExpiresAtUtc: timestamppb.New(time.Now().Add(ttl))},
+		RefreshToken: &proto.Token{Value: "refresh", ExpiresAtUtc: timestamppb.New(time.Now().Add(time.Hour))},
+	}, nil
+}
+
+func (s *fakeAuthServer) RefreshAccessToken(context.Context, *proto.RefreshAccessTokenRequest) (*proto.RefreshAccessTokenResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshes++
+	if s.refreshErr != nil {
+		return nil, s.refreshErr
+	}
+	return &proto.RefreshAccessTokenResponse{AccessToken: &proto.Token{Value: "access", ExpiresAtUtc: timestamppb.New(time.Now().Add(time.Hour))}}, nil
+}
+
+func (s *fakeAuthServer) GetTpuConfigs(ctx context.Context, _ *proto.GetTpuConfigsRequest) (*proto.GetTpuConfigsResponse, error) {
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.mu.Lock()
+	s.authorization = md.Get("authorization")
+	s.mu.Unlock()
+	return &proto.GetTpuConfigsResponse{}, nil
+}
+
+func (s *fakeAuthServer) refreshCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshes
+}
+
+func (s *fakeAuthServer) state() (int, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.challenges, s.authorization
+}
+
+type fakeMetrics struct {
+	mu      sync.Mutex
+	methods []string
+	errs    []error
+}
+
+func (m *fakeMetrics) ObserveRPC(method string, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.methods = append(m.methods, method)
+	m.errs = append(m.errs, err)
+}
+
+func newFakeAuthServer(t *testing.T, fake *fakeAuthServer) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	server := grpc.NewServer()
+	proto.RegisterAuthServiceServer(server, fake)
+	proto.RegisterRelayerServer(server, fake)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func Test_ClientOptions(t *testing.T) {
+	privateKey := solana.NewWallet().PrivateKey
+
+	t.Run("Defaults", func(t *testing.T) {
+		options := NewClientOptions(WithInsecure(), WithMaxMsgSize(1<<20, 1<<10), WithCompression("gzip"), WithContext(nil))
+		assert.NotNil(t, options.Ctx)
+		assert.True(t, options.Insecure)
+		assert.Equal(t, 1<<20, options.MaxRecvMsgSize)
+		assert.Equal(t, 1<<10, options.MaxSendMsgSize)
+		assert.Equal(t, "gzip", options.Compression)
+		assert.False(t, options.LazyAuth)
+
+		assert.False(t, NewClientOptions(WithInsecure(), WithTLSConfig(nil)).Insecure)
+	})
+
+	t.Run("Eager", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fake := &fakeAuthServer{}
+		metrics := &fakeMetrics{}
+		options := NewClientOptions(WithContext(ctx), WithInsecure(), WithCompression("gzip"), WithMetrics(metrics), WithTimeout(5*time.Second))
+
+		conn, auth, err := options.NewAuthenticationService(newFakeAuthServer(t, fake), privateKey, proto.Role_RELAYER)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		challenges, _ := fake.state()
+		assert.Equal(t, 1, challenges)
+		assert.Equal(t, "access", auth.BearerTokenValue())
+
+		// the interceptor adds the access token to calls made without AuthorizedContext
+		_, err = proto.NewRelayerClient(conn).GetTpuConfigs(ctx, &proto.GetTpuConfigsRequest{})
+		assert.NoError(t, err)
+		_, authorization := fake.state()
+		assert.Equal(t, []string{"Bearer access"}, authorization)
+
+		metrics.mu.Lock()
+		assert.Contains(t, metrics.methods, proto.AuthService_GenerateAuthChallenge_FullMethodName)
+		assert.Contains(t, metrics.methods, proto.Relayer_GetTpuConfigs_FullMethodName)
+		metrics.mu.Unlock()
+	})
+
+	t.Run("Lazy", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fake := &fakeAuthServer{}
+		options := NewClientOptions(WithContext(ctx), WithInsecure(), WithLazyAuth())
+
+		conn, auth, err := options.NewAuthenticationService(newFakeAuthServer(t, fake), privateKey, proto.Role_RELAYER)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		challenges, _ := fake.state()
+		assert.Zero(t, challenges)
+		assert.Empty(t, auth.BearerTokenValue())
+
+		relayer := proto.NewRelayerClient(conn)
+		for i := 0; i < 2; i++ {
+			_, err = relayer.GetTpuConfigs(ctx, &proto.GetTpuConfigsRequest{})
+			assert.NoError(t, err)
+		}
+		challenges, authorization := fake.state()
+		assert.Equal(t, 1, challenges)
+		assert.Equal(t, []string{"Bearer access"}, authorization)
+	})
+
+	t.Run("CallTimeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fake := &fakeAuthServer{block: make(chan struct{})}
+		options := NewClientOptions(WithContext(ctx), WithInsecure(), WithCallTimeout(50*time.Millisecond))
+
+		conn, err := options.Dial(newFakeAuthServer(t, fake), nil, proto.Role_RELAYER)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		_, err = proto.NewRelayerClient(conn).GetTpuConfigs(ctx, &proto.GetTpuConfigsRequest{})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+}
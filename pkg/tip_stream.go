@@ -0,0 +1,201 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TipStreamURL is the Jito websocket streaming the landed tips percentiles.
+const TipStreamURL = "ws://bundles-api-rest.jito.wtf/api/v1/bundles/tip_stream"
+
+var ErrEmptyTipStreamMessage = errors.New("tip stream message holds no tip info")
+
+type TipStreamInfo struct {
+	Time                        time.Time `json:"time"`
+	LandedTips25ThPercentile    float64   `json:"landed_tips_25th_percentile"`
+	LandedTips50ThPercentile    float64   `json:"landed_tips_50th_percentile"`
+	LandedTips75ThPercentile    float64   `json:"landed_tips_75th_percentile"`
+	LandedTips95ThPercentile    float64   `json:"landed_tips_95th_percentile"`
+	LandedTips99ThPercentile    float64   `json:"landed_tips_99th_percentile"`
+	EmaLandedTips50ThPercentile float64   `json:"ema_landed_tips_50th_percentile"`
+}
+
+// TipStreamConfig configures a TipStream, zero values fall back to sensible defaults.
+type TipStreamConfig struct {
+	// URL defaults to TipStreamURL.
+	URL string
+	// Dialer defaults to websocket.DefaultDialer.
+	Dialer *websocket.Dialer
+	// MinBackoff is the delay before the first reconnection attempt, defaults to 500ms.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between reconnection attempts, defaults to 30s.
+	MaxBackoff time.Duration
+}
+
+func (c TipStreamConfig) withDefaults() TipStreamConfig {
+	if c.URL == "" {
+		c.URL = TipStreamURL
+	}
+	if c.Dialer == nil {
+		c.Dialer = websocket.DefaultDialer
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff < c.MinBackoff {
+		c.MaxBackoff = max(30*time.Second, c.MinBackoff)
+	}
+	return c
+}
+
+// TipStream is a subscription to the tip stream websocket, reconnecting with backoff until its context is done.
+type TipStream struct {
+	config TipStreamConfig
+	ch     chan *TipStreamInfo
+
+	mu   sync.RWMutex
+	last *TipStreamInfo
+	err  error
+}
+
+// NewTipStream subscribes to the tip stream. It returns right away, connecting in the background.
+func NewTipStream(ctx context.Context, config TipStreamConfig) *TipStream {
+	s := &TipStream{
+		config: config.withDefaults(),
+		ch:     make(chan *TipStreamInfo, 1),
+	}
+
+	go s.run(ctx)
+
+	return s
+}
+
+// SubscribeTipStream establishes a connection to the Jito websocket and receives TipStreamInfo.
+// The channel is closed once ctx is done. Use NewTipStream for a configurable subscription.
+func SubscribeTipStream(ctx context.Context) (chan *TipStreamInfo, error) {
+	return NewTipStream(ctx, TipStreamConfig{}).ch, nil
+}
+
+// Updates returns the channel receiving tip infos, closed once the context of the stream is done.
+// Only the latest tip info is kept when the receiver falls behind.
+func (s *TipStream) Updates() <-chan *TipStreamInfo {
+	return s.ch
+}
+
+// Last returns the last tip info received, nil if none was received yet.
+func (s *TipStream) Last() *TipStreamInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Err returns the last connection or decoding error, nil once a tip info was received after it.
+func (s *TipStream) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+func (s *TipStream) run(ctx context.Context) {
+	defer close(s.ch)
+
+	backoff := s.config.MinBackoff
+	for {
+		received, err := s.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+
+		if received {
+			backoff = s.config.MinBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, s.config.MaxBackoff)
+	}
+}
+
+// stream reads tip infos from a single connection until it fails, reporting whether any was received.
+func (s *TipStream) stream(ctx context.Context) (bool, error) {
+	conn, _, err := s.config.Dialer.DialContext(ctx, s.config.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	received := false
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return received, err
+		}
+
+		info, err := decodeTipStreamMessage(msg)
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+		s.publish(info)
+	}
+}
+
+func (s *TipStream) publish(info *TipStreamInfo) {
+	s.mu.Lock()
+	s.last = info
+	s.err = nil
+	s.mu.Unlock()
+
+	for {
+		select {
+		case s.ch <- info:
+			return
+		default:
+		}
+
+		// drop the stale tip info the receiver has not consumed yet
+		select {
+		case <-s.ch:
+		default:
+		}
+	}
+}
+
+// decodeTipStreamMessage decodes a tip info, the websocket sends it either alone or wrapped in an array.
+func decodeTipStreamMessage(msg []byte) (*TipStreamInfo, error) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) > 0 && msg[0] == '[' {
+		var infos []*TipStreamInfo
+		if err := json.Unmarshal(msg, &infos); err != nil {
+			return nil, err
+		}
+		if len(infos) == 0 || infos[len(infos)-1] == nil {
+			return nil, ErrEmptyTipStreamMessage
+		}
+		return infos[len(infos)-1], nil
+	}
+
+	info := new(TipStreamInfo)
+	if err := json.Unmarshal(msg, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
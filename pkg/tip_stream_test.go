@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTipStreamServer serves one tip info per connection, then drops it so the client reconnects.
+func newTipStreamServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	var connections atomic.Int64
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		n := connections.Add(1)
+		info := []TipStreamInfo{{LandedTips50ThPercentile: float64(n) / 1e6}}
+		if err = conn.WriteJSON(info); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &connections
+}
+
+func Test_TipStream(t *testing.T) {
+	config := func(url string) TipStreamConfig {
+		return TipStreamConfig{URL: url, MinBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+	}
+
+	t.Run("Reconnects", func(t *testing.T) {
+		srv, connections := newTipStreamServer(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stream := NewTipStream(ctx, config("ws"+strings.TrimPrefix(srv.URL, "http")))
+
+		var last float64
+		for last < 3e-6 {
+			select {
+			case info := <-stream.Updates():
+				assert.Greater(t, info.LandedTips50ThPercentile, last)
+				last = info.LandedTips50ThPercentile
+			case <-time.After(5 * time.Second):
+				t.Fatal("tip stream did not reconnect")
+			}
+		}
+
+		assert.GreaterOrEqual(t, connections.Load(), int64(3))
+		assert.NotNil(t, stream.Last())
+	})
+
+	t.Run("ClosesOnCancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := NewTipStream(ctx, config("ws://127.0.0.1:1"))
+
+		assert.Eventually(t, func() bool { return stream.Err() != nil }, 5*time.Second, 5*time.Millisecond)
+		assert.Nil(t, stream.Last())
+
+		cancel()
+		select {
+		case _, ok := <-stream.Updates():
+			assert.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("tip stream channel was not closed")
+		}
+	})
+
+	t.Run("DecodeMessage", func(t *testing.T) {
+		info, err := decodeTipStreamMessage([]byte(`{"landed_tips_99th_percentile": 0.5}`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0.5, info.LandedTips99ThPercentile)
+		}
+
+		_, err = decodeTipStreamMessage([]byte(`[]`))
+		assert.ErrorIs(t, err, ErrEmptyTipStreamMessage)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/pvaronik/jito-go/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"log/slog"
 	"sync"
 	"time"
 )
@@ -19,22 +20,76 @@ type AuthenticationService struct {
 	BearerToken string
 	ExpiresAt   int64 // seconds
 	ErrChan     chan error
-	mu          sync.Mutex
+	// Logger, when set, logs the access token refresh failures.
+	Logger *slog.Logger
+	mu     sync.Mutex
+
+	// lifetime bounds the access token refresh
+	lifetime      context.Context
+	authenticated bool
+	refreshing    bool
+	authMu        sync.Mutex
+
+	// refreshToken and expiresAt are guarded by mu
+	refreshToken string
+	expiresAt    time.Time
 }
 
+var (
+	// refreshMargin is how long before its expiry the access token is refreshed.
+	refreshMargin = 15 * time.Second
+	// minRefreshBackoff and maxRefreshBackoff bound the wait between failed refreshes.
+	minRefreshBackoff = time.Second
+	maxRefreshBackoff = 30 * time.Second
+)
+
 func NewAuthenticationService(grpcConn *grpc.ClientConn, privateKey solana.PrivateKey) *AuthenticationService {
-	return &AuthenticationService{
-		GrpcCtx:     context.Background(),
-		AuthService: proto.NewAuthServiceClient(grpcConn),
-		KeyPair:     NewKeyPair(privateKey),
-		ErrChan:     make(chan error, 1),
-		mu:          sync.Mutex{},
+	return NewAuthenticationServiceContext(context.Background(), grpcConn, privateKey)
+}
+
+// NewAuthenticationServiceContext creates an AuthenticationService refreshing its access token until ctx is done.
+// A nil grpcConn leaves AuthService to be set by the caller.
+func NewAuthenticationServiceContext(ctx context.Context, grpcConn *grpc.ClientConn, privateKey solana.PrivateKey) *AuthenticationService {
+	as := &AuthenticationService{
+		GrpcCtx:  context.Background(),
+		KeyPair:  NewKeyPair(privateKey),
+		ErrChan:  make(chan error, 1),
+		mu:       sync.Mutex{},
+		lifetime: ctx,
 	}
+	if grpcConn != nil {
+		as.AuthService = proto.NewAuthServiceClient(grpcConn)
+	}
+	return as
 }
 
 // AuthenticateAndRefresh is a function that authenticates the client and refreshes the access token.
 func (as *AuthenticationService) AuthenticateAndRefresh(role proto.Role) error {
-	respChallenge, err := as.AuthService.GenerateAuthChallenge(as.GrpcCtx,
+	return as.Authenticate(as.GrpcCtx, role)
+}
+
+// EnsureAuthenticated authenticates with role unless already authenticated, see Authenticate.
+// Failed authentications are retried on the next call.
+func (as *AuthenticationService) EnsureAuthenticated(ctx context.Context, role proto.Role) error {
+	as.authMu.Lock()
+	defer as.authMu.Unlock()
+
+	if as.authenticated {
+		return nil
+	}
+	return as.authenticate(ctx, role)
+}
+
+// Authenticate authenticates the client with ctx and refreshes the access token in the background,
+// until the context of the service is done. Authenticating again replaces the tokens, the refresh is not duplicated.
+func (as *AuthenticationService) Authenticate(ctx context.Context, role proto.Role) error {
+	as.authMu.Lock()
+	defer as.authMu.Unlock()
+	return as.authenticate(ctx, role)
+}
+
+func (as *AuthenticationService) authenticate(ctx context.Context, role proto.Role) error {
+	respChallenge, err := as.AuthService.GenerateAuthChallenge(ctx,
 		&proto.GenerateAuthChallengeRequest{
 			Role:   role,
 			Pubkey: as.KeyPair.PublicKey.Bytes(),
@@ -51,7 +106,7 @@ func (as *AuthenticationService) AuthenticateAndRefresh(role proto.Role) error {
 		return err
 	}
 
-	respToken, err := as.AuthService.GenerateAuthTokens(as.GrpcCtx, &proto.GenerateAuthTokensRequest{
+	respToken, err := as.AuthService.GenerateAuthTokens(ctx, &proto.GenerateAuthTokensRequest{
 		Challenge:       challenge,
 		SignedChallenge: sig,
 		ClientPubkey:    as.KeyPair.PublicKey.Bytes(),
@@ -61,29 +116,76 @@ func (as *AuthenticationService) AuthenticateAndRefresh(role proto.Role) error {
 	}
 
 	as.updateAuthorizationMetadata(respToken.AccessToken)
+	as.mu.Lock()
+	as.refreshToken = respToken.RefreshToken.GetValue()
+	as.mu.Unlock()
+	as.authenticated = true
+
+	// a single refresh loop serves the service, it picks up the refresh token of the latest authentication
+	if !as.refreshing {
+		as.refreshing = true
+
+		lifetime := as.lifetime
+		if lifetime == nil {
+			lifetime = context.Background()
+		}
+		go as.refreshLoop(lifetime)
+	}
+
+	return nil
+}
+
+// refreshLoop refreshes the access token before it expires until lifetime is done,
+// retrying failed refreshes with a growing backoff.
+func (as *AuthenticationService) refreshLoop(lifetime context.Context) {
+	backoff := minRefreshBackoff
+	next := as.untilRefresh()
 
-	go func() {
-		for {
+	for {
+		timer := time.NewTimer(next)
+		select {
+		case <-lifetime.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		as.mu.Lock()
+		refreshToken := as.refreshToken
+		as.mu.Unlock()
+
+		resp, err := as.AuthService.RefreshAccessToken(lifetime, &proto.RefreshAccessTokenRequest{RefreshToken: refreshToken})
+		if err != nil {
+			if lifetime.Err() != nil {
+				return
+			}
+
+			err = fmt.Errorf("failed to refresh access token: %w", err)
+			if as.Logger != nil {
+				as.Logger.Warn("failed to refresh access token", "err", err, "retry_in", backoff)
+			}
+			// nobody may be reading ErrChan, the refresh must not block on it
 			select {
-			case <-as.GrpcCtx.Done():
-				as.ErrChan <- as.GrpcCtx.Err()
+			case as.ErrChan <- err:
 			default:
-				var resp *proto.RefreshAccessTokenResponse
-				resp, err = as.AuthService.RefreshAccessToken(as.GrpcCtx, &proto.RefreshAccessTokenRequest{
-					RefreshToken: respToken.RefreshToken.Value,
-				})
-				if err != nil {
-					as.ErrChan <- fmt.Errorf("failed to refresh access token: %w", err)
-					continue
-				}
-
-				as.updateAuthorizationMetadata(resp.AccessToken)
-				time.Sleep(time.Until(resp.AccessToken.ExpiresAtUtc.AsTime()) - 15*time.Second)
 			}
+
+			next = backoff
+			backoff = min(backoff*2, maxRefreshBackoff)
+			continue
 		}
-	}()
 
-	return nil
+		as.updateAuthorizationMetadata(resp.AccessToken)
+		backoff = minRefreshBackoff
+		next = as.untilRefresh()
+	}
+}
+
+// untilRefresh returns how long to wait before refreshing the access token.
+func (as *AuthenticationService) untilRefresh() time.Duration {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return max(time.Until(as.expiresAt)-refreshMargin, 0)
 }
 
 // updateAuthorizationMetadata updates headers of the gRPC connection.
@@ -93,7 +195,28 @@ func (as *AuthenticationService) updateAuthorizationMetadata(token *proto.Token)
 
 	as.GrpcCtx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token.Value))
 	as.BearerToken = token.Value
-	as.ExpiresAt = token.ExpiresAtUtc.Seconds
+	as.ExpiresAt = token.ExpiresAtUtc.GetSeconds()
+	as.expiresAt = token.ExpiresAtUtc.AsTime()
+}
+
+// BearerTokenValue returns the current access token, empty if not authenticated.
+func (as *AuthenticationService) BearerTokenValue() string {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return as.BearerToken
+}
+
+// AuthorizedContext returns a copy of ctx carrying the current authorization headers, ctx if not authenticated.
+func (as *AuthenticationService) AuthorizedContext(ctx context.Context) context.Context {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if as.GrpcCtx == nil {
+		return ctx
+	}
+
+	md, _ := metadata.FromOutgoingContext(as.GrpcCtx)
+	return metadata.NewOutgoingContext(ctx, md)
 }
 
 func (as *AuthenticationService) generateChallengeSignature(challenge []byte) ([]byte, error) {
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/pvaronik/jito-go/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func Test_AuthenticationServiceRefresh(t *testing.T) {
+	privateKey := solana.NewWallet().PrivateKey
+
+	newService := func(t *testing.T, ctx context.Context, fake *fakeAuthServer) *AuthenticationService {
+		conn, err := grpc.Dial(newFakeAuthServer(t, fake), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		t.Cleanup(func() { conn.Close() })
+
+		return NewAuthenticationServiceContext(ctx, conn, privateKey)
+	}
+
+	t.Run("SingleRefreshLoop", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fake := &fakeAuthServer{accessTTL: refreshMargin + 100*time.Millisecond}
+		auth := newService(t, ctx, fake)
+
+		for i := 0; i < 2; i++ {
+			if !assert.NoError(t, auth.Authenticate(ctx, proto.Role_SEARCHER)) {
+				t.FailNow()
+			}
+		}
+
+		assert.Eventually(t, func() bool { return fake.refreshCount() == 1 }, time.Second, 10*time.Millisecond)
+		// the refreshed token expires in an hour, another loop would have refreshed again by now
+		time.Sleep(200 * time.Millisecond)
+		assert.Equal(t, 1, fake.refreshCount())
+	})
+
+	t.Run("StoppedWithContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		fake := &fakeAuthServer{accessTTL: refreshMargin + 100*time.Millisecond}
+		auth := newService(t, ctx, fake)
+
+		if !assert.NoError(t, auth.Authenticate(ctx, proto.Role_SEARCHER)) {
+			t.FailNow()
+		}
+		cancel()
+
+		time.Sleep(300 * time.Millisecond)
+		assert.Zero(t, fake.refreshCount())
+	})
+
+	t.Run("BackoffOnFailure", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fake := &fakeAuthServer{accessTTL: refreshMargin, refreshErr: errors.New("unavailable")}
+		auth := newService(t, ctx, fake)
+
+		if !assert.NoError(t, auth.Authenticate(ctx, proto.Role_SEARCHER)) {
+			t.FailNow()
+		}
+
+		assert.Eventually(t, func() bool { return fake.refreshCount() == 1 }, time.Second, 10*time.Millisecond)
+		time.Sleep(minRefreshBackoff / 2)
+		assert.Equal(t, 1, fake.refreshCount())
+
+		select {
+		case err := <-auth.ErrChan:
+			assert.ErrorContains(t, err, "failed to refresh access token")
+		default:
+			assert.Fail(t, "refresh failure not reported")
+		}
+	})
+}
@@ -1,12 +1,12 @@
 package pkg
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"github.com/blocto/solana-go-sdk/types"
+	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/gorilla/websocket"
-	"time"
+	"github.com/pvaronik/jito-go/proto"
 )
 
 // ExtractSigFromTx extracts the transaction's signature.
@@ -14,6 +14,33 @@ func ExtractSigFromTx(tx *solana.Transaction) solana.Signature {
 	return tx.Signatures[0]
 }
 
+// ExtractSigFromSerializedTx extracts the signature of a transaction in its wire format.
+func ExtractSigFromSerializedTx(data []byte) (solana.Signature, error) {
+	count, size, err := bin.DecodeCompactU16(data)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+
+	if count == 0 || len(data) < size+solana.SignatureLength {
+		return solana.Signature{}, errors.New("transaction has no signature")
+	}
+
+	return solana.SignatureFromBytes(data[size : size+solana.SignatureLength]), nil
+}
+
+// BatchExtractSigFromPackets extracts the signature of every transaction packet.
+func BatchExtractSigFromPackets(packets []*proto.Packet) ([]solana.Signature, error) {
+	sigs := make([]solana.Signature, 0, len(packets))
+	for i, packet := range packets {
+		sig, err := ExtractSigFromSerializedTx(packet.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
 func BatchExtractSigFromTx(txns []types.Transaction) []types.Signature {
 	sigs := make([]types.Signature, 0, len(txns))
 	for _, tx := range txns {
@@ -35,43 +62,6 @@ func NewKeyPair(privateKey solana.PrivateKey) *Keypair {
 	return &Keypair{PrivateKey: privateKey, PublicKey: privateKey.PublicKey()}
 }
 
-const tipStreamURL = "ws://bundles-api-rest.jito.wtf/api/v1/bundles/tip_stream"
-
-type TipStreamInfo struct {
-	Time                        time.Time `json:"time"`
-	LandedTips25ThPercentile    float64   `json:"landed_tips_25th_percentile"`
-	LandedTips50ThPercentile    float64   `json:"landed_tips_50th_percentile"`
-	LandedTips75ThPercentile    float64   `json:"landed_tips_75th_percentile"`
-	LandedTips95ThPercentile    float64   `json:"landed_tips_95th_percentile"`
-	LandedTips99ThPercentile    float64   `json:"landed_tips_99th_percentile"`
-	EmaLandedTips50ThPercentile float64   `json:"ema_landed_tips_50th_percentile"`
-}
-
-// SubscribeTipStream establishes a connection to the Jito websocket and receives TipStreamInfo.
-func SubscribeTipStream(ctx context.Context) (chan *TipStreamInfo, error) {
-	dialer := websocket.Dialer{}
-	ch := make(chan *TipStreamInfo)
-
-	conn, _, err := dialer.Dial(tipStreamURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, nil
-		default:
-			var r *TipStreamInfo
-			if err = conn.ReadJSON(r); err != nil {
-				continue
-			}
-
-			ch <- r
-		}
-	}
-}
-
 // GenerateKeypair creates a new Solana Keypair.
 func GenerateKeypair() *Keypair {
 	wallet := solana.NewWallet()